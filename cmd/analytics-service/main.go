@@ -1,15 +1,28 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	_ "time/tzdata" // tz query param must resolve IANA zones in minimal images
+
+	"google.golang.org/grpc"
 
 	"analytics-service/internal/auth"
+	"analytics-service/internal/cache"
 	"analytics-service/internal/config"
 	"analytics-service/internal/db"
+	grpchandler "analytics-service/internal/grpc"
 	httphandler "analytics-service/internal/http"
 	"analytics-service/internal/http/middleware"
 	"analytics-service/internal/logger"
+	"analytics-service/internal/metrics"
+	"analytics-service/internal/model"
 	"analytics-service/internal/repository"
 	"analytics-service/internal/service"
 )
@@ -21,28 +34,163 @@ func main() {
 		os.Exit(1)
 	}
 
-	appLogger := logger.New(cfg.Environment)
+	appLogger := logger.New(cfg.Environment, cfg.LogLevel)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	database, err := db.New(cfg, appLogger)
 	if err != nil {
 		appLogger.Fatal().Err(err).Msg("failed to connect database")
 	}
 
-	scopeRepo := repository.NewScopeRepository(database)
-	analyticsRepo := repository.NewAnalyticsRepository(database)
-	analyticsService := service.NewAnalyticsService(scopeRepo, analyticsRepo, cfg.Analytics.DefaultRangeDays, cfg.Analytics.MaxRangeDays)
+	roleScopes := make(map[model.UserRole]model.ScopeType, len(cfg.Scope.RoleScopes))
+	for role, scopeType := range cfg.Scope.RoleScopes {
+		roleScopes[model.UserRole(role)] = model.ScopeType(scopeType)
+	}
+	scopeRepo := repository.NewScopeRepository(database, repository.ScopeOptions{
+		RoleScopes:   roleScopes,
+		DefaultScope: model.ScopeType(cfg.Scope.DefaultScope),
+		CacheTTL:     cfg.Scope.CacheTTL,
+		CacheSize:    cfg.Scope.CacheSize,
+	})
+	analyticsRepo := repository.NewAnalyticsRepository(database, appLogger, repository.AnalyticsOptions{
+		NameCacheTTL:            cfg.Analytics.NameCacheTTL,
+		CameraDegradedErrorRate: cfg.Analytics.CameraDegradedErrorRate,
+	})
+	var responseCache *cache.Cache
+	if cfg.Cache.RedisURL != "" {
+		responseCache, err = cache.New(ctx, cfg.Cache.RedisURL, cfg.Cache.TTL, appLogger)
+		if err != nil {
+			appLogger.Fatal().Err(err).Msg("failed to connect redis")
+		}
+	}
+	cameraScopes := make([]model.ScopeType, 0, len(cfg.Analytics.DashboardCameraScopes))
+	for _, scopeType := range cfg.Analytics.DashboardCameraScopes {
+		cameraScopes = append(cameraScopes, model.ScopeType(scopeType))
+	}
+	analyticsService := service.NewAnalyticsService(scopeRepo, analyticsRepo, service.Options{
+		DefaultRangeDays:        cfg.Analytics.DefaultRangeDays,
+		MaxRangeDays:            cfg.Analytics.MaxRangeDays,
+		ActiveTripMaxAge:        cfg.Analytics.ActiveTripMaxAge,
+		DashboardCameraScopes:   cameraScopes,
+		AreaNeglectAfter:        cfg.Analytics.AreaNeglectAfter,
+		MaxNameLength:           cfg.Analytics.MaxNameLength,
+		ViolationSeverities:     cfg.Analytics.ViolationSeverities,
+		Cache:                   responseCache,
+		DedupInFlight:           cfg.Cache.DedupInFlight,
+		DuplicateTripWindow:     cfg.Analytics.DuplicateTripWindow,
+		DedupTrips:              cfg.Analytics.DedupTrips,
+		DefaultLeaderLimit:      cfg.Analytics.DefaultLeaderLimit,
+		DefaultPerformanceLimit: cfg.Analytics.DefaultPerformanceLimit,
+		TonnesPerM3:             cfg.Analytics.TonnesPerM3,
+		ViolationRateMinTrips:   cfg.Analytics.ViolationRateMinTrips,
+		Log:                     appLogger,
+	})
 
 	tokenParser := auth.NewParser(cfg.Auth.AccessSecret)
 
-	handler := httphandler.NewHandler(analyticsService, appLogger)
-	authMiddleware := middleware.Auth(tokenParser)
-	router := httphandler.NewRouter(handler, authMiddleware, cfg.Environment)
+	handler := httphandler.NewHandler(analyticsService, appLogger, cfg.Analytics.DefaultPageSize, cfg.Analytics.MaxPageSize)
+	authMiddleware := middleware.Auth(tokenParser, cfg.Auth.RequireDriverID)
+	sqlDB, err := database.DB()
+	if err != nil {
+		appLogger.Fatal().Err(err).Msg("failed to access database pool")
+	}
+	appMetrics := metrics.New(sqlDB)
+	gzipMinSize := -1
+	if cfg.HTTP.GzipEnabled {
+		gzipMinSize = cfg.HTTP.GzipMinSize
+	}
+	router := httphandler.NewRouter(handler, authMiddleware, appMetrics, cfg.Analytics.QueryTimeout, gzipMinSize, cfg.Environment)
+
+	scheduler := service.NewRefreshScheduler(analyticsService, cfg.Analytics.MVRefreshInterval, appLogger)
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		scheduler.Run(ctx)
+	}()
 
 	addr := fmt.Sprintf("%s:%d", cfg.HTTP.Host, cfg.HTTP.Port)
-	appLogger.Info().Str("addr", addr).Msg("starting analytics service")
+	server := &http.Server{
+		Addr:    addr,
+		Handler: router,
+	}
+
+	serverErr := make(chan error, 2)
+	go func() {
+		appLogger.Info().Str("addr", addr).Msg("starting analytics service")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Enabled {
+		grpcServer = grpc.NewServer(grpc.ChainUnaryInterceptor(
+			grpchandler.AuthInterceptor(tokenParser, cfg.Auth.RequireDriverID),
+			grpchandler.TimeoutInterceptor(cfg.Analytics.QueryTimeout),
+		))
+		grpchandler.NewServer(analyticsService, appLogger).Register(grpcServer)
+
+		grpcAddr := fmt.Sprintf("%s:%d", cfg.HTTP.Host, cfg.GRPC.Port)
+		listener, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			appLogger.Fatal().Err(err).Str("addr", grpcAddr).Msg("failed to listen for grpc")
+		}
+		go func() {
+			appLogger.Info().Str("addr", grpcAddr).Msg("starting analytics grpc service")
+			if err := grpcServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+				serverErr <- err
+			}
+		}()
+	}
 
-	if err := router.Run(addr); err != nil {
+	exitCode := 0
+	select {
+	case <-ctx.Done():
+		appLogger.Info().Msg("shutdown signal received")
+	case err := <-serverErr:
 		appLogger.Error().Err(err).Msg("failed to start server")
-		os.Exit(1)
+		exitCode = 1
+		stop()
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		appLogger.Error().Err(err).Msg("http server shutdown")
+		exitCode = 1
+	}
+	if grpcServer != nil {
+		stopGRPC(shutdownCtx, grpcServer)
+	}
+	<-schedulerDone
+
+	if err := sqlDB.Close(); err != nil {
+		appLogger.Error().Err(err).Msg("close database")
+	}
+	if err := responseCache.Close(); err != nil {
+		appLogger.Error().Err(err).Msg("close redis")
+	}
+
+	appLogger.Info().Msg("analytics service stopped")
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+}
+
+// stopGRPC drains in-flight calls and forces the server down once the
+// shutdown timeout passes.
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
 	}
 }
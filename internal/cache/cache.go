@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+const keyPrefix = "analytics:cache:"
+
+// Cache stores serialized analytics responses in Redis. A nil *Cache is a
+// valid, disabled cache: lookups always miss and writes are dropped.
+//
+// Redis failures never fail a request; they are logged and treated as a
+// miss so the caller falls back to the database.
+type Cache struct {
+	client *redis.Client
+	ttl    time.Duration
+	log    zerolog.Logger
+}
+
+// New connects to the Redis instance at url (redis:// or rediss://) and
+// verifies it answers a PING.
+func New(ctx context.Context, url string, ttl time.Duration, log zerolog.Logger) (*Cache, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("parse redis url: %w", err)
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("ping redis: %w", err)
+	}
+	return &Cache{client: client, ttl: ttl, log: log}, nil
+}
+
+// Key derives a cache key for endpoint from the JSON encoding of parts.
+// Callers must pass everything that affects the response, including the
+// resolved scope, so that differently scoped users never share an entry.
+// It also works on a nil *Cache, as the key identifies a request for
+// in-flight deduplication as well.
+func (c *Cache) Key(endpoint string, parts ...interface{}) string {
+	payload, err := json.Marshal(parts)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(payload)
+	return keyPrefix + endpoint + ":" + hex.EncodeToString(sum[:])
+}
+
+// Get decodes the entry stored under key into dest and reports whether it
+// was found.
+func (c *Cache) Get(ctx context.Context, key string, dest interface{}) bool {
+	if c == nil || key == "" {
+		return false
+	}
+	payload, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			c.log.Warn().Err(err).Str("key", key).Msg("cache get")
+		}
+		return false
+	}
+	if err := json.Unmarshal(payload, dest); err != nil {
+		c.log.Warn().Err(err).Str("key", key).Msg("cache decode")
+		return false
+	}
+	return true
+}
+
+// Set stores value under key for the configured TTL.
+func (c *Cache) Set(ctx context.Context, key string, value interface{}) {
+	if c == nil || key == "" {
+		return
+	}
+	payload, err := json.Marshal(value)
+	if err != nil {
+		c.log.Warn().Err(err).Str("key", key).Msg("cache encode")
+		return
+	}
+	if err := c.client.Set(ctx, key, payload, c.ttl).Err(); err != nil {
+		c.log.Warn().Err(err).Str("key", key).Msg("cache set")
+	}
+}
+
+// Flush removes every cached response, e.g. after the materialized views
+// were refreshed.
+func (c *Cache) Flush(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	if err := c.flush(ctx); err != nil {
+		c.log.Warn().Err(err).Msg("cache flush")
+	}
+}
+
+func (c *Cache) flush(ctx context.Context) error {
+	iter := c.client.Scan(ctx, 0, keyPrefix+"*", 500).Iterator()
+	keys := make([]string, 0, 500)
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == cap(keys) {
+			if err := c.client.Unlink(ctx, keys...).Err(); err != nil {
+				return err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return c.client.Unlink(ctx, keys...).Err()
+	}
+	return nil
+}
+
+// Close releases the Redis connection pool.
+func (c *Cache) Close() error {
+	if c == nil {
+		return nil
+	}
+	return c.client.Close()
+}
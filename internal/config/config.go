@@ -2,13 +2,26 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
 )
 
 type HTTPConfig struct {
-	Host string
-	Port int
+	Host            string
+	Port            int
+	ShutdownTimeout time.Duration
+	GzipEnabled     bool
+	GzipMinSize     int
+}
+
+// GRPCConfig controls the gRPC listener, which binds to the HTTP host on
+// its own port.
+type GRPCConfig struct {
+	Enabled bool
+	Port    int
 }
 
 type DBConfig struct {
@@ -19,20 +32,82 @@ type DBConfig struct {
 }
 
 type AuthConfig struct {
-	AccessSecret string
+	AccessSecret    string
+	RequireDriverID bool
 }
 
 type AnalyticsConfig struct {
-	DefaultRangeDays int
-	MaxRangeDays     int
+	DefaultRangeDays  int
+	MaxRangeDays      int
+	DefaultPageSize   int
+	MaxPageSize       int
+	MVRefreshInterval time.Duration
+	ActiveTripMaxAge  time.Duration
+	QueryTimeout      time.Duration
+	// DashboardCameraScopes lists the scope types (CITY, KGU, CONTRACTOR,
+	// TECHNICAL) that receive camera load on the dashboard.
+	DashboardCameraScopes []string
+	AreaNeglectAfter      time.Duration
+	// MaxNameLength truncates entity names in responses to this many
+	// characters; zero disables truncation.
+	MaxNameLength int
+	// ViolationSeverities overrides the trip status → severity mapping
+	// (STATUS=SEVERITY pairs).
+	ViolationSeverities map[string]string
+	// DuplicateTripWindow is the entry time distance below which a trip of
+	// the same driver and vehicle counts as a duplicate.
+	DuplicateTripWindow time.Duration
+	// DedupTrips drops duplicate trips from the dashboard counts.
+	DedupTrips bool
+	// NameCacheTTL keeps entity names in memory; zero disables the cache.
+	NameCacheTTL time.Duration
+	// CameraDegradedErrorRate is the error rate (0–1) above which a camera
+	// counts as DEGRADED.
+	CameraDegradedErrorRate float64
+	// DefaultLeaderLimit and DefaultPerformanceLimit size the leader lists
+	// of trips/violations and performance when top is omitted.
+	DefaultLeaderLimit      int
+	DefaultPerformanceLimit int
+	// TonnesPerM3 is the density used to report volumes in tonnes. It is a
+	// single factor: trips and tickets record no waste type to pick a
+	// per-type density by.
+	TonnesPerM3 float64
+	// ViolationRateMinTrips is the fewest trips an entity needs to appear
+	// among the violation leaders ranked by rate.
+	ViolationRateMinTrips int64
+}
+
+type ScopeConfig struct {
+	// RoleScopes adds or overrides role → scope type mappings
+	// (ROLE=SCOPE pairs).
+	RoleScopes map[string]string
+	// DefaultScope applies to unmapped roles; empty means deny.
+	DefaultScope string
+	CacheTTL     time.Duration
+	CacheSize    int
+}
+
+type CacheConfig struct {
+	// RedisURL enables the response cache; empty disables it.
+	RedisURL string
+	TTL      time.Duration
+	// DedupInFlight shares one query run between concurrent identical
+	// requests; it works with or without Redis.
+	DedupInFlight bool
 }
 
 type Config struct {
 	Environment string
-	HTTP        HTTPConfig
-	DB          DBConfig
-	Auth        AuthConfig
-	Analytics   AnalyticsConfig
+	// LogLevel is a zerolog level name; empty means debug in development
+	// and info elsewhere.
+	LogLevel  string
+	HTTP      HTTPConfig
+	GRPC      GRPCConfig
+	DB        DBConfig
+	Auth      AuthConfig
+	Analytics AnalyticsConfig
+	Scope     ScopeConfig
+	Cache     CacheConfig
 }
 
 func Load() (*Config, error) {
@@ -45,14 +120,47 @@ func Load() (*Config, error) {
 	v.AddConfigPath("./internal/config")
 
 	v.AutomaticEnv()
+	v.SetDefault("SHUTDOWN_TIMEOUT", "20s")
+	v.SetDefault("HTTP_GZIP_ENABLED", true)
+	v.SetDefault("HTTP_GZIP_MIN_SIZE", 1024)
+	v.SetDefault("GRPC_ENABLED", true)
+	v.SetDefault("GRPC_PORT", 7086)
+	v.SetDefault("AUTH_REQUIRE_DRIVER_ID", true)
+	v.SetDefault("ANALYTICS_MV_REFRESH_INTERVAL", "15m")
+	v.SetDefault("ANALYTICS_ACTIVE_TRIP_MAX_AGE", "12h")
+	v.SetDefault("ANALYTICS_QUERY_TIMEOUT", "30s")
+	v.SetDefault("ANALYTICS_DASHBOARD_CAMERA_SCOPES", "CITY,KGU,CONTRACTOR,TECHNICAL")
+	v.SetDefault("ANALYTICS_AREA_NEGLECT_AFTER", "48h")
+	v.SetDefault("ANALYTICS_MAX_NAME_LENGTH", 120)
+	v.SetDefault("ANALYTICS_DUPLICATE_TRIP_WINDOW", "30s")
+	v.SetDefault("ANALYTICS_DEDUP_TRIPS", false)
+	v.SetDefault("ANALYTICS_NAME_CACHE_TTL", "0s")
+	v.SetDefault("ANALYTICS_CAMERA_DEGRADED_ERROR_RATE", 0.2)
+	v.SetDefault("ANALYTICS_DEFAULT_LEADER_LIMIT", 5)
+	v.SetDefault("ANALYTICS_DEFAULT_PERFORMANCE_LIMIT", 10)
+	v.SetDefault("ANALYTICS_TONNES_PER_M3", 0.5)
+	v.SetDefault("ANALYTICS_VIOLATION_RATE_MIN_TRIPS", 10)
+	v.SetDefault("CACHE_TTL", "60s")
+	v.SetDefault("CACHE_DEDUP_INFLIGHT", true)
+	v.SetDefault("SCOPE_CACHE_TTL", "5m")
+	v.SetDefault("SCOPE_CACHE_SIZE", 1024)
+	v.SetDefault("SCOPE_DEFAULT", "DENY")
 
 	_ = v.ReadInConfig()
 
 	cfg := &Config{
 		Environment: v.GetString("APP_ENV"),
+		LogLevel:    strings.ToLower(strings.TrimSpace(v.GetString("LOG_LEVEL"))),
 		HTTP: HTTPConfig{
-			Host: v.GetString("HTTP_HOST"),
-			Port: v.GetInt("HTTP_PORT"),
+			Host:            v.GetString("HTTP_HOST"),
+			Port:            v.GetInt("HTTP_PORT"),
+			ShutdownTimeout: v.GetDuration("SHUTDOWN_TIMEOUT"),
+			GzipEnabled:     v.GetBool("HTTP_GZIP_ENABLED"),
+			GzipMinSize:     v.GetInt("HTTP_GZIP_MIN_SIZE"),
+		},
+		GRPC: GRPCConfig{
+			Enabled: v.GetBool("GRPC_ENABLED"),
+			Port:    v.GetInt("GRPC_PORT"),
 		},
 		DB: DBConfig{
 			DSN:             v.GetString("DB_DSN"),
@@ -61,12 +169,50 @@ func Load() (*Config, error) {
 			ConnMaxLifetime: v.GetString("DB_CONN_MAX_LIFETIME"),
 		},
 		Auth: AuthConfig{
-			AccessSecret: v.GetString("JWT_ACCESS_SECRET"),
+			AccessSecret:    v.GetString("JWT_ACCESS_SECRET"),
+			RequireDriverID: v.GetBool("AUTH_REQUIRE_DRIVER_ID"),
 		},
 		Analytics: AnalyticsConfig{
-			DefaultRangeDays: v.GetInt("ANALYTICS_DEFAULT_RANGE_DAYS"),
-			MaxRangeDays:     v.GetInt("ANALYTICS_MAX_RANGE_DAYS"),
+			DefaultRangeDays:        v.GetInt("ANALYTICS_DEFAULT_RANGE_DAYS"),
+			MaxRangeDays:            v.GetInt("ANALYTICS_MAX_RANGE_DAYS"),
+			DefaultPageSize:         v.GetInt("ANALYTICS_DEFAULT_PAGE_SIZE"),
+			MaxPageSize:             v.GetInt("ANALYTICS_MAX_PAGE_SIZE"),
+			MVRefreshInterval:       v.GetDuration("ANALYTICS_MV_REFRESH_INTERVAL"),
+			ActiveTripMaxAge:        v.GetDuration("ANALYTICS_ACTIVE_TRIP_MAX_AGE"),
+			QueryTimeout:            v.GetDuration("ANALYTICS_QUERY_TIMEOUT"),
+			DashboardCameraScopes:   parseScopeList(v.GetString("ANALYTICS_DASHBOARD_CAMERA_SCOPES")),
+			AreaNeglectAfter:        v.GetDuration("ANALYTICS_AREA_NEGLECT_AFTER"),
+			MaxNameLength:           v.GetInt("ANALYTICS_MAX_NAME_LENGTH"),
+			DuplicateTripWindow:     v.GetDuration("ANALYTICS_DUPLICATE_TRIP_WINDOW"),
+			DedupTrips:              v.GetBool("ANALYTICS_DEDUP_TRIPS"),
+			NameCacheTTL:            v.GetDuration("ANALYTICS_NAME_CACHE_TTL"),
+			CameraDegradedErrorRate: v.GetFloat64("ANALYTICS_CAMERA_DEGRADED_ERROR_RATE"),
+			DefaultLeaderLimit:      v.GetInt("ANALYTICS_DEFAULT_LEADER_LIMIT"),
+			DefaultPerformanceLimit: v.GetInt("ANALYTICS_DEFAULT_PERFORMANCE_LIMIT"),
+			TonnesPerM3:             v.GetFloat64("ANALYTICS_TONNES_PER_M3"),
+			ViolationRateMinTrips:   v.GetInt64("ANALYTICS_VIOLATION_RATE_MIN_TRIPS"),
 		},
+		Cache: CacheConfig{
+			RedisURL:      v.GetString("REDIS_URL"),
+			TTL:           v.GetDuration("CACHE_TTL"),
+			DedupInFlight: v.GetBool("CACHE_DEDUP_INFLIGHT"),
+		},
+	}
+
+	roleScopes, err := parsePairs("SCOPE_ROLE_MAP", v.GetString("SCOPE_ROLE_MAP"), "ROLE=SCOPE")
+	if err != nil {
+		return nil, err
+	}
+	severities, err := parsePairs("ANALYTICS_VIOLATION_SEVERITIES", v.GetString("ANALYTICS_VIOLATION_SEVERITIES"), "STATUS=SEVERITY")
+	if err != nil {
+		return nil, err
+	}
+	cfg.Analytics.ViolationSeverities = severities
+	cfg.Scope.RoleScopes = roleScopes
+	cfg.Scope.CacheTTL = v.GetDuration("SCOPE_CACHE_TTL")
+	cfg.Scope.CacheSize = v.GetInt("SCOPE_CACHE_SIZE")
+	if defaultScope := strings.ToUpper(strings.TrimSpace(v.GetString("SCOPE_DEFAULT"))); defaultScope != "DENY" {
+		cfg.Scope.DefaultScope = defaultScope
 	}
 
 	if cfg.HTTP.Host == "" {
@@ -75,6 +221,9 @@ func Load() (*Config, error) {
 	if cfg.HTTP.Port == 0 {
 		cfg.HTTP.Port = 7085
 	}
+	if cfg.HTTP.ShutdownTimeout <= 0 {
+		cfg.HTTP.ShutdownTimeout = 20 * time.Second
+	}
 	if cfg.Environment == "" {
 		cfg.Environment = "development"
 	}
@@ -84,6 +233,15 @@ func Load() (*Config, error) {
 	if cfg.Analytics.MaxRangeDays <= 0 {
 		cfg.Analytics.MaxRangeDays = 90
 	}
+	if cfg.Analytics.MaxPageSize <= 0 {
+		cfg.Analytics.MaxPageSize = 500
+	}
+	if cfg.Analytics.DefaultPageSize <= 0 {
+		cfg.Analytics.DefaultPageSize = 50
+	}
+	if cfg.Analytics.DefaultPageSize > cfg.Analytics.MaxPageSize {
+		cfg.Analytics.DefaultPageSize = cfg.Analytics.MaxPageSize
+	}
 
 	if err := validate(cfg); err != nil {
 		return nil, err
@@ -96,8 +254,153 @@ func validate(cfg *Config) error {
 	if cfg.DB.DSN == "" {
 		return fmt.Errorf("DB_DSN is required")
 	}
+	if cfg.LogLevel != "" {
+		if _, err := zerolog.ParseLevel(cfg.LogLevel); err != nil {
+			return fmt.Errorf("LOG_LEVEL must be a level such as debug, info or warn")
+		}
+	}
 	if cfg.Auth.AccessSecret == "" {
 		return fmt.Errorf("JWT_ACCESS_SECRET is required")
 	}
+	if cfg.HTTP.GzipMinSize < 0 {
+		return fmt.Errorf("HTTP_GZIP_MIN_SIZE must not be negative")
+	}
+	if cfg.GRPC.Enabled {
+		if cfg.GRPC.Port <= 0 || cfg.GRPC.Port > 65535 {
+			return fmt.Errorf("GRPC_PORT must be a valid port")
+		}
+		if cfg.GRPC.Port == cfg.HTTP.Port {
+			return fmt.Errorf("GRPC_PORT must differ from HTTP_PORT")
+		}
+	}
+	if cfg.Analytics.MVRefreshInterval < 0 {
+		return fmt.Errorf("ANALYTICS_MV_REFRESH_INTERVAL must not be negative")
+	}
+	if cfg.Analytics.ActiveTripMaxAge < 0 {
+		return fmt.Errorf("ANALYTICS_ACTIVE_TRIP_MAX_AGE must not be negative")
+	}
+	if cfg.Analytics.QueryTimeout < 0 {
+		return fmt.Errorf("ANALYTICS_QUERY_TIMEOUT must not be negative")
+	}
+	if cfg.Analytics.AreaNeglectAfter < 0 {
+		return fmt.Errorf("ANALYTICS_AREA_NEGLECT_AFTER must not be negative")
+	}
+	if cfg.Analytics.MaxNameLength < 0 {
+		return fmt.Errorf("ANALYTICS_MAX_NAME_LENGTH must not be negative")
+	}
+	if rate := cfg.Analytics.CameraDegradedErrorRate; rate < 0 || rate > 1 {
+		return fmt.Errorf("ANALYTICS_CAMERA_DEGRADED_ERROR_RATE must be between 0 and 1")
+	}
+	if limit := cfg.Analytics.DefaultLeaderLimit; limit < 1 || limit > 50 {
+		return fmt.Errorf("ANALYTICS_DEFAULT_LEADER_LIMIT must be between 1 and 50")
+	}
+	if limit := cfg.Analytics.DefaultPerformanceLimit; limit < 1 || limit > 50 {
+		return fmt.Errorf("ANALYTICS_DEFAULT_PERFORMANCE_LIMIT must be between 1 and 50")
+	}
+	if cfg.Analytics.TonnesPerM3 <= 0 {
+		return fmt.Errorf("ANALYTICS_TONNES_PER_M3 must be positive")
+	}
+	if cfg.Analytics.ViolationRateMinTrips < 1 {
+		return fmt.Errorf("ANALYTICS_VIOLATION_RATE_MIN_TRIPS must be at least 1")
+	}
+	if cfg.Analytics.NameCacheTTL < 0 {
+		return fmt.Errorf("ANALYTICS_NAME_CACHE_TTL must not be negative")
+	}
+	if cfg.Analytics.DuplicateTripWindow <= 0 {
+		return fmt.Errorf("ANALYTICS_DUPLICATE_TRIP_WINDOW must be positive")
+	}
+	if cfg.Cache.RedisURL != "" && cfg.Cache.TTL <= 0 {
+		return fmt.Errorf("CACHE_TTL must be positive when REDIS_URL is set")
+	}
+	for _, scope := range cfg.Analytics.DashboardCameraScopes {
+		if !isScopeType(scope) {
+			return fmt.Errorf("ANALYTICS_DASHBOARD_CAMERA_SCOPES: unknown scope %q", scope)
+		}
+	}
+	for role, scope := range cfg.Scope.RoleScopes {
+		if !isScopeType(scope) {
+			return fmt.Errorf("SCOPE_ROLE_MAP: unknown scope %q for role %s", scope, role)
+		}
+	}
+	for status, severity := range cfg.Analytics.ViolationSeverities {
+		if !isViolationStatus(status) {
+			return fmt.Errorf("ANALYTICS_VIOLATION_SEVERITIES: unknown violation status %q", status)
+		}
+		if !isSeverity(severity) {
+			return fmt.Errorf("ANALYTICS_VIOLATION_SEVERITIES: unknown severity %q for status %s", severity, status)
+		}
+	}
+	// Only the technical scope is safe enough to hand to unknown roles.
+	if cfg.Scope.DefaultScope != "" && cfg.Scope.DefaultScope != "TECHNICAL" {
+		return fmt.Errorf("SCOPE_DEFAULT must be DENY or TECHNICAL")
+	}
+	if cfg.Scope.CacheTTL < 0 {
+		return fmt.Errorf("SCOPE_CACHE_TTL must not be negative")
+	}
+	if cfg.Scope.CacheTTL > 0 && cfg.Scope.CacheSize <= 0 {
+		return fmt.Errorf("SCOPE_CACHE_SIZE must be positive when SCOPE_CACHE_TTL is set")
+	}
 	return nil
 }
+
+func isScopeType(scope string) bool {
+	switch scope {
+	case "CITY", "KGU", "CONTRACTOR", "TECHNICAL":
+		return true
+	default:
+		return false
+	}
+}
+
+func isViolationStatus(status string) bool {
+	switch status {
+	case "NO_LPR_EVENT", "NO_VOLUME_EVENT", "CAMERA_ERROR", "MISMATCH_PLATE":
+		return true
+	default:
+		return false
+	}
+}
+
+func isSeverity(severity string) bool {
+	switch severity {
+	case "HIGH", "MEDIUM", "LOW":
+		return true
+	default:
+		return false
+	}
+}
+
+// parsePairs parses KEY=VALUE pairs separated by commas, e.g.
+// "DISPATCHER=CITY,AUDITOR=TECHNICAL", upper-casing both sides. format names
+// the expected shape in errors.
+func parsePairs(name, raw, format string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("%s: invalid entry %q, expected %s", name, pair, format)
+		}
+		mapping[key] = value
+	}
+	return mapping, nil
+}
+
+// parseScopeList splits a comma separated scope list; "NONE" yields an empty
+// list so camera load can be switched off for everyone.
+func parseScopeList(raw string) []string {
+	scopes := make([]string, 0, 4)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.ToUpper(strings.TrimSpace(part))
+		if part == "" || part == "NONE" {
+			continue
+		}
+		scopes = append(scopes, part)
+	}
+	return scopes
+}
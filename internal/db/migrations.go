@@ -9,7 +9,14 @@ import (
 var migrationStatements = []string{
 	`CREATE EXTENSION IF NOT EXISTS "uuid-ossp";`,
 	`CREATE EXTENSION IF NOT EXISTS "pgcrypto";`,
-	`CREATE EXTENSION IF NOT EXISTS "postgis";`,
+	// PostGIS is optional: without it areas are served with a null geometry.
+	`DO $$
+	BEGIN
+		CREATE EXTENSION IF NOT EXISTS "postgis";
+	EXCEPTION WHEN OTHERS THEN
+		RAISE NOTICE 'postgis unavailable: %', SQLERRM;
+	END
+	$$;`,
 	`DO $$
 	BEGIN
 		IF EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = 'trips') AND
@@ -37,6 +44,8 @@ var migrationStatements = []string{
 		IF EXISTS (SELECT 1 FROM pg_matviews WHERE matviewname = 'mv_trip_daily') THEN
 			CREATE INDEX IF NOT EXISTS idx_mv_trip_daily_bucket ON mv_trip_daily (bucket);
 			CREATE INDEX IF NOT EXISTS idx_mv_trip_daily_contractor ON mv_trip_daily (contractor_id, created_by_org_id);
+			CREATE UNIQUE INDEX IF NOT EXISTS uq_mv_trip_daily ON mv_trip_daily
+				(bucket, contractor_id, created_by_org_id, cleaning_area_id, driver_id, vehicle_id, polygon_id) NULLS NOT DISTINCT;
 		END IF;
 	END
 	$$;`,
@@ -65,6 +74,8 @@ var migrationStatements = []string{
 		IF EXISTS (SELECT 1 FROM pg_matviews WHERE matviewname = 'mv_violation_daily') THEN
 			CREATE INDEX IF NOT EXISTS idx_mv_violation_daily_bucket ON mv_violation_daily (bucket);
 			CREATE INDEX IF NOT EXISTS idx_mv_violation_daily_contractor ON mv_violation_daily (contractor_id, created_by_org_id);
+			CREATE UNIQUE INDEX IF NOT EXISTS uq_mv_violation_daily ON mv_violation_daily
+				(bucket, contractor_id, created_by_org_id, cleaning_area_id, driver_id, violation_type) NULLS NOT DISTINCT;
 		END IF;
 	END
 	$$;`,
@@ -92,6 +103,8 @@ var migrationStatements = []string{
 		IF EXISTS (SELECT 1 FROM pg_matviews WHERE matviewname = 'mv_contract_daily') THEN
 			CREATE INDEX IF NOT EXISTS idx_mv_contract_daily_bucket ON mv_contract_daily (bucket);
 			CREATE INDEX IF NOT EXISTS idx_mv_contract_daily_contract ON mv_contract_daily (contract_id);
+			CREATE UNIQUE INDEX IF NOT EXISTS uq_mv_contract_daily ON mv_contract_daily
+				(bucket, contract_id, contractor_id, created_by_org_id) NULLS NOT DISTINCT;
 		END IF;
 	END
 	$$;`,
@@ -123,6 +136,8 @@ var migrationStatements = []string{
 		IF EXISTS (SELECT 1 FROM pg_matviews WHERE matviewname = 'mv_cleaning_area_daily') THEN
 			CREATE INDEX IF NOT EXISTS idx_mv_cleaning_area_daily_bucket ON mv_cleaning_area_daily (bucket);
 			CREATE INDEX IF NOT EXISTS idx_mv_cleaning_area_daily_area ON mv_cleaning_area_daily (cleaning_area_id);
+			CREATE UNIQUE INDEX IF NOT EXISTS uq_mv_cleaning_area_daily ON mv_cleaning_area_daily
+				(bucket, cleaning_area_id, contractor_id, created_by_org_id) NULLS NOT DISTINCT;
 		END IF;
 	END
 	$$;`,
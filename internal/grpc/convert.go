@@ -0,0 +1,301 @@
+package grpc
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	analyticsv1 "analytics-service/api/analytics/v1"
+	"analytics-service/internal/model"
+)
+
+func dashboardToProto(d *model.DashboardMetrics) *analyticsv1.Dashboard {
+	out := &analyticsv1.Dashboard{
+		Stats: &analyticsv1.DashboardStats{
+			ActiveTrips:       d.Stats.ActiveTrips,
+			CompletedTrips:    d.Stats.CompletedTrips,
+			TicketsInProgress: d.Stats.TicketsInProgress,
+			Violations:        d.Stats.Violations,
+			ActiveDrivers:     d.Stats.ActiveDrivers,
+			ActiveVehicles:    d.Stats.ActiveVehicles,
+		},
+		Contractors: &analyticsv1.DashboardContractors{
+			Active: entityMetricsToProto(d.Contractors.Active),
+			Idle:   entityMetricsToProto(d.Contractors.Idle),
+		},
+		Cameras:      cameraMetricsToProto(d.Cameras),
+		Map:          &analyticsv1.MapSummary{},
+		GeneratedFor: dateRangeToProto(d.GeneratedFor),
+	}
+	if prev := d.Stats.PreviousPeriod; prev != nil {
+		out.Stats.PreviousPeriod = &analyticsv1.DashboardPeriodCompare{
+			Range:                   dateRangeToProto(prev.Range),
+			CompletedTrips:          prev.CompletedTrips,
+			Violations:              prev.Violations,
+			CompletedTripsChangePct: prev.CompletedTripsChangePct,
+			ViolationsChangePct:     prev.ViolationsChangePct,
+		}
+	}
+	if d.Meta != nil {
+		out.GeneratedAt = timestamppb.New(d.Meta.GeneratedAt)
+	}
+	for _, area := range d.Areas {
+		out.Areas = append(out.Areas, &analyticsv1.CleaningAreaActivity{
+			CleaningAreaId: area.CleaningAreaID.String(),
+			Trips:          area.Trips,
+			ActiveTrips:    area.ActiveTrips,
+			HasViolations:  area.HasViolations,
+			TripHeat:       area.TripHeat,
+		})
+	}
+	for _, contract := range d.Contracts {
+		out.Contracts = append(out.Contracts, &analyticsv1.ContractProgress{
+			ContractId:          contract.ContractID.String(),
+			Name:                contract.Name,
+			ContractorId:        contract.ContractorID.String(),
+			ContractorName:      contract.ContractorName,
+			BudgetTotal:         contract.BudgetTotal,
+			TotalCost:           contract.TotalCost,
+			BudgetProgress:      contract.BudgetProgress,
+			MinimalVolumeM3:     contract.MinimalVolume,
+			TotalVolumeM3:       contract.TotalVolume,
+			VolumeProgress:      contract.VolumeProgress,
+			HasUsageData:        contract.HasUsageData,
+			UiStatus:            contract.UIStatus,
+			Result:              contract.Result,
+			StartAt:             timestamppb.New(contract.StartAt),
+			EndAt:               timestamppb.New(contract.EndAt),
+			ProjectedEndCost:    contract.ProjectedEndCost,
+			ProjectedOverBudget: contract.ProjectedOverBudget,
+			AvgTripsPerDay:      contract.AvgTripsPerDay,
+			AvgVolumePerDay:     contract.AvgVolumePerDay,
+		})
+	}
+	for _, area := range d.Map.Areas {
+		out.Map.Areas = append(out.Map.Areas, &analyticsv1.MapAreaState{
+			Id:             area.ID.String(),
+			HasTrips:       area.HasTrips,
+			HasActiveTrips: area.HasActiveTrips,
+			HasViolations:  area.HasViolations,
+			Intensity:      area.Intensity,
+		})
+	}
+	for _, polygon := range d.Map.Polygons {
+		out.Map.Polygons = append(out.Map.Polygons, &analyticsv1.MapPolygonState{
+			Id:        polygon.ID.String(),
+			Name:      polygon.Name,
+			TripCount: polygon.TripCount,
+			VolumeM3:  polygon.VolumeM3,
+		})
+	}
+	for _, camera := range d.Map.Cameras {
+		out.Map.Cameras = append(out.Map.Cameras, &analyticsv1.MapCameraState{
+			Id:          camera.ID.String(),
+			Name:        camera.Name,
+			Events:      camera.Events,
+			ErrorEvents: camera.ErrorEvents,
+		})
+	}
+	return out
+}
+
+func tripAnalyticsToProto(a *model.TripAnalytics) *analyticsv1.TripAnalytics {
+	out := &analyticsv1.TripAnalytics{
+		Series:          seriesToProto(a.Series),
+		SeriesSummary:   seriesSummaryToProto(a.SeriesSummary),
+		VolumeSeries:    seriesToProto(a.VolumeSeries),
+		VolumeSummary:   seriesSummaryToProto(a.VolumeSummary),
+		StatusBreakdown: violationBreakdownToProto(a.StatusBreakdown),
+		TopDrivers:      entityMetricsToProto(a.TopDrivers),
+		TopContractors:  entityMetricsToProto(a.TopContractors),
+		DurationStats: &analyticsv1.TripDurationStats{
+			AvgMinutes: a.DurationStats.AvgMinutes,
+			P50Minutes: a.DurationStats.P50Minutes,
+			P90Minutes: a.DurationStats.P90Minutes,
+			P95Minutes: a.DurationStats.P95Minutes,
+			MaxMinutes: a.DurationStats.MaxMinutes,
+		},
+		VolumeStats: &analyticsv1.TripVolumeStats{
+			AvgVolume:       a.VolumeStats.AvgVolume,
+			MaxVolume:       a.VolumeStats.MaxVolume,
+			MinVolume:       a.VolumeStats.MinVolume,
+			AvgExitVolume:   a.VolumeStats.AvgExitVolume,
+			MaxExitVolume:   a.VolumeStats.MaxExitVolume,
+			MinExitVolume:   a.VolumeStats.MinExitVolume,
+			TotalVolume:     a.VolumeStats.TotalVolume,
+			TotalExitVolume: a.VolumeStats.TotalExitVolume,
+			EntryReadings:   a.VolumeStats.EntryReadings,
+			ExitReadings:    a.VolumeStats.ExitReadings,
+			AvgNetVolume:    a.VolumeStats.AvgNetVolume,
+			TotalNetVolume:  a.VolumeStats.TotalNetVolume,
+		},
+	}
+	if len(a.EntitySeries) > 0 {
+		out.EntitySeries = make(map[string]*analyticsv1.Series, len(a.EntitySeries))
+		for key, points := range a.EntitySeries {
+			out.EntitySeries[key] = &analyticsv1.Series{Points: seriesToProto(points)}
+		}
+	}
+	return out
+}
+
+func violationAnalyticsToProto(a *model.ViolationAnalytics) *analyticsv1.ViolationAnalytics {
+	out := &analyticsv1.ViolationAnalytics{
+		Series:         seriesToProto(a.Series),
+		Breakdown:      violationBreakdownToProto(a.Breakdown),
+		TopContractors: entityMetricsToProto(a.TopContractors),
+		TopDrivers:     entityMetricsToProto(a.TopDrivers),
+		TopCameras:     cameraMetricsToProto(a.TopCameras),
+	}
+	for _, item := range a.Severities {
+		out.Severities = append(out.Severities, &analyticsv1.SeverityBreakdown{
+			Severity: item.Severity,
+			Count:    item.Count,
+			Share:    item.Share,
+		})
+	}
+	return out
+}
+
+func violationBreakdownToProto(items []model.ViolationBreakdown) []*analyticsv1.ViolationBreakdown {
+	out := make([]*analyticsv1.ViolationBreakdown, 0, len(items))
+	for _, item := range items {
+		out = append(out, &analyticsv1.ViolationBreakdown{
+			Type:     item.Type,
+			Severity: item.Severity,
+			Count:    item.Count,
+			Share:    item.Share,
+		})
+	}
+	return out
+}
+
+func tripDetailsToProto(d *model.TripDetails) *analyticsv1.TripDetails {
+	out := &analyticsv1.TripDetails{
+		TripId:              d.TripID.String(),
+		TicketId:            optionalID(d.TicketID),
+		TicketName:          d.TicketName,
+		DriverId:            optionalID(d.DriverID),
+		DriverName:          d.DriverName,
+		VehicleId:           optionalID(d.VehicleID),
+		VehiclePlate:        d.VehiclePlate,
+		ContractorId:        optionalID(d.ContractorID),
+		ContractorName:      d.ContractorName,
+		CleaningAreaId:      optionalID(d.CleaningAreaID),
+		PolygonId:           optionalID(d.PolygonID),
+		Status:              d.Status,
+		EntryAt:             timestamppb.New(d.EntryAt),
+		ExitAt:              optionalTimestamp(d.ExitAt),
+		DetectedVolumeEntry: d.DetectedVolumeEntry,
+		DetectedVolumeExit:  d.DetectedVolumeExit,
+		Events: &analyticsv1.TripEventDetails{
+			EntryLpr:    tripEventToProto(d.Events.EntryLPR),
+			ExitLpr:     tripEventToProto(d.Events.ExitLPR),
+			EntryVolume: tripEventToProto(d.Events.EntryVolume),
+			ExitVolume:  tripEventToProto(d.Events.ExitVolume),
+		},
+	}
+	for _, violation := range d.Violations {
+		out.Violations = append(out.Violations, &analyticsv1.ViolationRecord{
+			Type:   violation.Type,
+			Source: violation.Source,
+			At:     timestamppb.New(violation.At),
+			Note:   violation.Note,
+		})
+	}
+	return out
+}
+
+func tripEventToProto(event *model.TripEvent) *analyticsv1.TripEvent {
+	if event == nil {
+		return nil
+	}
+	return &analyticsv1.TripEvent{
+		EventId:    event.EventID.String(),
+		CameraId:   event.CameraID.String(),
+		PhotoUrl:   event.PhotoURL,
+		CapturedAt: timestamppb.New(event.Captured),
+	}
+}
+
+func entityMetricsToProto(items []model.EntityMetric) []*analyticsv1.EntityMetric {
+	out := make([]*analyticsv1.EntityMetric, 0, len(items))
+	for _, item := range items {
+		out = append(out, &analyticsv1.EntityMetric{
+			Id:            item.ID.String(),
+			Name:          item.Name,
+			Count:         item.Count,
+			Volume:        item.Volume,
+			Share:         item.Share,
+			FullName:      item.FullName,
+			TripCount:     item.TripCount,
+			ViolationRate: item.ViolationRate,
+		})
+	}
+	return out
+}
+
+func cameraMetricsToProto(items []model.CameraLoadMetric) []*analyticsv1.CameraLoadMetric {
+	out := make([]*analyticsv1.CameraLoadMetric, 0, len(items))
+	for _, item := range items {
+		out = append(out, &analyticsv1.CameraLoadMetric{
+			CameraId:     item.CameraID.String(),
+			CameraName:   item.CameraName,
+			PolygonId:    optionalID(item.PolygonID),
+			PolygonName:  item.PolygonName,
+			LprEvents:    item.LprEvents,
+			VolumeEvents: item.VolumeEvents,
+			ErrorEvents:  item.ErrorEvents,
+			ErrorRate:    item.ErrorRate,
+			Status:       item.Status,
+		})
+	}
+	return out
+}
+
+func seriesToProto(points []model.SeriesPoint) []*analyticsv1.SeriesPoint {
+	out := make([]*analyticsv1.SeriesPoint, 0, len(points))
+	for _, point := range points {
+		out = append(out, &analyticsv1.SeriesPoint{
+			Bucket:  timestamppb.New(point.Bucket),
+			Count:   point.Count,
+			Value:   point.Value,
+			Partial: point.Partial,
+		})
+	}
+	return out
+}
+
+func seriesSummaryToProto(summary model.SeriesSummary) *analyticsv1.SeriesSummary {
+	return &analyticsv1.SeriesSummary{
+		Total:        summary.Total,
+		TotalValue:   summary.TotalValue,
+		AvgPerBucket: summary.AvgPerBucket,
+		PeakBucket:   optionalTimestamp(summary.PeakBucket),
+		PeakValue:    summary.PeakValue,
+	}
+}
+
+func dateRangeToProto(rng model.DateRange) *analyticsv1.DateRange {
+	return &analyticsv1.DateRange{
+		From:   timestamppb.New(rng.From),
+		To:     timestamppb.New(rng.To),
+		Strict: rng.Strict,
+	}
+}
+
+func optionalID(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	return proto.String(id.String())
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
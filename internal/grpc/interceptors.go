@@ -0,0 +1,75 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"analytics-service/internal/auth"
+	"analytics-service/internal/model"
+)
+
+const (
+	authMetadataKey = "authorization"
+	bearerPrefix    = "Bearer"
+)
+
+type principalKey struct{}
+
+// AuthInterceptor validates the bearer token in the authorization metadata
+// the same way middleware.Auth validates the HTTP header, and stores the
+// principal in the call context.
+func AuthInterceptor(parser *auth.Parser, requireDriverID bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(authMetadataKey)
+		if len(values) == 0 || values[0] == "" {
+			return nil, status.Error(codes.Unauthenticated, "authorization metadata missing")
+		}
+
+		parts := strings.SplitN(values[0], " ", 2)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], bearerPrefix) {
+			return nil, status.Error(codes.Unauthenticated, "invalid authorization metadata")
+		}
+
+		claims, err := parser.Parse(parts[1])
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+
+		principal := model.Principal{
+			UserID:   claims.UserID,
+			OrgID:    claims.OrgID,
+			OrgIDs:   claims.OrgIDs,
+			Role:     claims.Role,
+			DriverID: claims.DriverID,
+		}
+		if requireDriverID && !principal.HasDriverIdentity() {
+			return nil, status.Error(codes.Unauthenticated, "driver token missing driver_id")
+		}
+
+		return handler(context.WithValue(ctx, principalKey{}, principal), req)
+	}
+}
+
+// TimeoutInterceptor bounds each call like the HTTP query timeout middleware.
+func TimeoutInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if timeout <= 0 {
+			return handler(ctx, req)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+func principalFrom(ctx context.Context) (model.Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(model.Principal)
+	return principal, ok
+}
@@ -0,0 +1,321 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	analyticsv1 "analytics-service/api/analytics/v1"
+	"analytics-service/internal/model"
+	"analytics-service/internal/service"
+)
+
+// Server exposes the dashboard, trip and violation reports over gRPC. It is
+// a thin adapter over AnalyticsService, like the HTTP handler.
+type Server struct {
+	analyticsv1.UnimplementedAnalyticsServiceServer
+
+	analytics *service.AnalyticsService
+	log       zerolog.Logger
+}
+
+func NewServer(analytics *service.AnalyticsService, log zerolog.Logger) *Server {
+	return &Server{
+		analytics: analytics,
+		log:       log,
+	}
+}
+
+// Register attaches the analytics service to a gRPC server.
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	analyticsv1.RegisterAnalyticsServiceServer(registrar, s)
+}
+
+func (s *Server) GetDashboard(ctx context.Context, req *analyticsv1.GetDashboardRequest) (*analyticsv1.Dashboard, error) {
+	principal, ok := principalFrom(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing principal")
+	}
+
+	bbox, err := parseBBox(req.GetBbox())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var cameraID *uuid.UUID
+	if raw := strings.TrimSpace(req.GetCameraId()); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid camera_id")
+		}
+		cameraID = &id
+	}
+
+	dashboard, err := s.analytics.GetDashboard(ctx, principal, parseDateRange(req.GetRange()), bbox, cameraID)
+	if err != nil {
+		return nil, s.handleError(ctx, "GetDashboard", err)
+	}
+
+	return dashboardToProto(dashboard), nil
+}
+
+func (s *Server) GetTripAnalytics(ctx context.Context, req *analyticsv1.GetTripAnalyticsRequest) (*analyticsv1.TripAnalytics, error) {
+	principal, ok := principalFrom(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing principal")
+	}
+
+	filter, err := parseAnalyticsFilter(req.GetFilter())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.GetTop() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "top must be a positive integer")
+	}
+	rankBy := strings.ToLower(strings.TrimSpace(req.GetRankBy()))
+	if rankBy != "" && rankBy != model.RankByCount && rankBy != model.RankByVolume {
+		return nil, status.Error(codes.InvalidArgument, "rank_by must be count or volume")
+	}
+
+	filter, _, err = s.analytics.ResolveContractorName(ctx, principal, filter)
+	if err != nil {
+		return nil, s.handleError(ctx, "GetTripAnalytics", err)
+	}
+
+	analytics, err := s.analytics.GetTripAnalytics(ctx, principal, filter, int(req.GetTop()), rankBy)
+	if err != nil {
+		return nil, s.handleError(ctx, "GetTripAnalytics", err)
+	}
+
+	return tripAnalyticsToProto(analytics), nil
+}
+
+func (s *Server) GetViolationAnalytics(ctx context.Context, req *analyticsv1.GetViolationAnalyticsRequest) (*analyticsv1.ViolationAnalytics, error) {
+	principal, ok := principalFrom(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing principal")
+	}
+
+	filter, err := parseAnalyticsFilter(req.GetFilter())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.GetTop() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "top must be a positive integer")
+	}
+
+	leaders, err := parseLeaderOptions(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	leaders.Limit = int(req.GetTop())
+
+	filter, _, err = s.analytics.ResolveContractorName(ctx, principal, filter)
+	if err != nil {
+		return nil, s.handleError(ctx, "GetViolationAnalytics", err)
+	}
+
+	analytics, err := s.analytics.GetViolationAnalytics(ctx, principal, filter, leaders)
+	if err != nil {
+		return nil, s.handleError(ctx, "GetViolationAnalytics", err)
+	}
+
+	return violationAnalyticsToProto(analytics), nil
+}
+
+func (s *Server) GetTripDetails(ctx context.Context, req *analyticsv1.GetTripDetailsRequest) (*analyticsv1.TripDetails, error) {
+	principal, ok := principalFrom(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing principal")
+	}
+
+	tripID, err := uuid.Parse(strings.TrimSpace(req.GetTripId()))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid trip id")
+	}
+
+	details, err := s.analytics.GetTripDetails(ctx, principal, tripID)
+	if err != nil {
+		return nil, s.handleError(ctx, "GetTripDetails", err)
+	}
+
+	return tripDetailsToProto(details), nil
+}
+
+// handleError maps service errors to gRPC status codes, mirroring the HTTP
+// status mapping of the handler.
+func (s *Server) handleError(ctx context.Context, method string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, service.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidFilter):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrInvalidInterval), errors.Is(err, service.ErrContractorRequired), errors.Is(err, service.ErrInvalidSort), errors.Is(err, service.ErrTooManyMatches), errors.Is(err, service.ErrRangeTooLarge), errors.Is(err, service.ErrInvalidOrgFilter):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(ctx.Err(), context.Canceled):
+		s.log.Debug().Err(err).Str("method", method).Msg("client cancelled call")
+		return status.Error(codes.Canceled, "call cancelled")
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		s.log.Warn().Err(err).Str("method", method).Msg("analytics query timed out")
+		return status.Error(codes.DeadlineExceeded, "query timed out, narrow the date range or filters")
+	default:
+		s.log.Error().Err(err).Str("method", method).Str("error_type", "unhandled").Msg("grpc handler error")
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// parseDateRange converts the request range; unset bounds stay zero so the
+// service applies its defaults.
+func parseDateRange(rng *analyticsv1.DateRange) model.DateRange {
+	var out model.DateRange
+	if rng == nil {
+		return out
+	}
+	if rng.GetFrom() != nil {
+		out.From = rng.GetFrom().AsTime()
+	}
+	if rng.GetTo() != nil {
+		out.To = rng.GetTo().AsTime()
+	}
+	out.Strict = rng.GetStrict()
+	return out
+}
+
+func parseBBox(bbox *analyticsv1.BBox) (*model.BBox, error) {
+	if bbox == nil {
+		return nil, nil
+	}
+	out := &model.BBox{MinLon: bbox.GetMinLon(), MinLat: bbox.GetMinLat(), MaxLon: bbox.GetMaxLon(), MaxLat: bbox.GetMaxLat()}
+	for _, value := range []float64{out.MinLon, out.MinLat, out.MaxLon, out.MaxLat} {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, errors.New("invalid bbox coordinate")
+		}
+	}
+	if out.MinLon < -180 || out.MaxLon > 180 || out.MinLat < -90 || out.MaxLat > 90 {
+		return nil, errors.New("bbox longitudes must be within -180..180 and latitudes within -90..90")
+	}
+	if out.MinLon >= out.MaxLon || out.MinLat >= out.MaxLat {
+		return nil, errors.New("bbox min coordinates must be less than max coordinates")
+	}
+	return out, nil
+}
+
+func parseAnalyticsFilter(req *analyticsv1.AnalyticsFilter) (model.AnalyticsFilter, error) {
+	filter := model.AnalyticsFilter{Range: parseDateRange(req.GetRange()), GroupBy: model.GroupByDay}
+
+	ids := []struct {
+		name  string
+		value string
+		dst   **uuid.UUID
+	}{
+		{"contractor_id", req.GetContractorId(), &filter.ContractorID},
+		{"driver_id", req.GetDriverId(), &filter.DriverID},
+		{"vehicle_id", req.GetVehicleId(), &filter.VehicleID},
+		{"polygon_id", req.GetPolygonId(), &filter.PolygonID},
+		{"camera_id", req.GetCameraId(), &filter.CameraID},
+		{"created_by_org_id", req.GetCreatedByOrgId(), &filter.CreatedByOrgID},
+	}
+	for _, field := range ids {
+		raw := strings.TrimSpace(field.value)
+		if raw == "" {
+			continue
+		}
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return model.AnalyticsFilter{}, fmt.Errorf("invalid %s", field.name)
+		}
+		*field.dst = &id
+	}
+	filter.ContractorName = strings.TrimSpace(req.GetContractorName())
+
+	switch strings.ToLower(strings.TrimSpace(req.GetGroupBy())) {
+	case "", "day":
+	case "hour":
+		filter.GroupBy = model.GroupByHour
+	case "week":
+		filter.GroupBy = model.GroupByWeek
+	case "month":
+		filter.GroupBy = model.GroupByMonth
+	default:
+		return model.AnalyticsFilter{}, errors.New("group_by must be hour, day, week or month")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(req.GetGroupByEntity())) {
+	case "":
+	case "contractor":
+		filter.GroupByEntity = model.GroupByEntityContractor
+	case "driver":
+		filter.GroupByEntity = model.GroupByEntityDriver
+	case "area":
+		filter.GroupByEntity = model.GroupByEntityArea
+	default:
+		return model.AnalyticsFilter{}, errors.New("group_by_entity must be contractor, driver or area")
+	}
+
+	for _, tripStatus := range req.GetStatuses() {
+		tripStatus = strings.ToUpper(strings.TrimSpace(tripStatus))
+		if !model.IsTripStatus(tripStatus) {
+			return model.AnalyticsFilter{}, fmt.Errorf("invalid status: unknown trip status %q", tripStatus)
+		}
+		filter.Statuses = append(filter.Statuses, tripStatus)
+	}
+
+	for _, severity := range req.GetSeverities() {
+		severity = strings.ToUpper(strings.TrimSpace(severity))
+		if !model.IsSeverity(severity) {
+			return model.AnalyticsFilter{}, fmt.Errorf("invalid severity: expected HIGH, MEDIUM or LOW, got %q", severity)
+		}
+		filter.Severities = append(filter.Severities, severity)
+	}
+
+	if tz := strings.TrimSpace(req.GetTz()); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil || tz == "Local" {
+			return model.AnalyticsFilter{}, fmt.Errorf("invalid tz: unknown IANA time zone %q", tz)
+		}
+		filter.TZ = loc
+	}
+
+	return filter, nil
+}
+
+func parseLeaderOptions(req *analyticsv1.GetViolationAnalyticsRequest) (model.LeaderOptions, error) {
+	opts := model.LeaderOptions{MinCount: req.GetLeaderMinCount(), MinShare: req.GetLeaderMinShare()}
+	if opts.MinCount < 0 {
+		return opts, errors.New("leader_min_count must be a non-negative integer")
+	}
+	if math.IsNaN(opts.MinShare) || opts.MinShare < 0 || opts.MinShare > 1 {
+		return opts, errors.New("leader_min_share must be a number between 0 and 1")
+	}
+	switch order := strings.ToLower(strings.TrimSpace(req.GetLeaderOrder())); order {
+	case "", model.LeaderOrderCount:
+		opts.OrderBy = model.LeaderOrderCount
+	case model.LeaderOrderShare:
+		opts.OrderBy = model.LeaderOrderShare
+	default:
+		return opts, errors.New("leader_order must be count or share")
+	}
+	switch by := strings.ToLower(strings.TrimSpace(req.GetBy())); by {
+	case "", model.LeaderByCount:
+		opts.By = model.LeaderByCount
+	case model.LeaderByRate:
+		if opts.OrderBy == model.LeaderOrderShare {
+			return opts, errors.New("leader_order=share cannot be combined with by=rate")
+		}
+		opts.By = model.LeaderByRate
+	default:
+		return opts, errors.New("by must be count or rate")
+	}
+	return opts, nil
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"analytics-service/internal/http/middleware"
+	"analytics-service/internal/model"
+)
+
+// conditionalJSON renders body like c.JSON but buffers it first to derive an
+// ETag, and answers a matching If-None-Match with 304 and no body. The tag
+// also hashes the principal and the query string, so the same payload served
+// to another scope or for another range never shares a tag.
+func (h *Handler) conditionalJSON(c *gin.Context, principal model.Principal, body any) {
+	h.conditionalJSONTagged(c, principal, body, nil)
+}
+
+// conditionalJSONTagged is conditionalJSON with the ETag derived from tagged
+// instead of the rendered body, for responses carrying values such as
+// computation timestamps that change on every request without the data
+// changing. A nil tagged hashes the body.
+func (h *Handler) conditionalJSONTagged(c *gin.Context, principal model.Principal, body, tagged any) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		h.requestLog(c).Error().Err(err).Msg("marshal response")
+		c.JSON(http.StatusInternalServerError, errorResponse("internal error"))
+		return
+	}
+	hashed := payload
+	if tagged != nil {
+		if hashed, err = json.Marshal(tagged); err != nil {
+			h.requestLog(c).Error().Err(err).Msg("marshal response")
+			c.JSON(http.StatusInternalServerError, errorResponse("internal error"))
+			return
+		}
+	}
+
+	etag := responseETag(principal, c.Request.URL.RawQuery, hashed)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+	c.Writer.Header().Add("Vary", "Authorization")
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", payload)
+}
+
+func responseETag(principal model.Principal, query string, payload []byte) string {
+	hash := sha256.New()
+	driverID := ""
+	if principal.DriverID != nil {
+		driverID = principal.DriverID.String()
+	}
+	fmt.Fprintf(hash, "%s|%v|%s|%s|%s\n", principal.UserID, principal.Organizations(), principal.Role, driverID, query)
+	hash.Write(payload)
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header lists etag. Weak
+// validators compare equal to their strong form, as RFC 9110 requires for
+// If-None-Match, and the tag of the gzip encoding (see middleware.Gzip)
+// matches the tag it was derived from.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if trimmed, ok := strings.CutSuffix(candidate, middleware.GzipETagSuffix+`"`); ok {
+			candidate = trimmed + `"`
+		}
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"analytics-service/internal/model"
+)
+
+var seriesHeader = []string{"bucket", "count", "volume"}
+
+// WriteSeriesCSV writes trip count and volume series as bucket,count,volume rows.
+// Points from both series are joined on their bucket; a bucket missing from one
+// side is written with zero for that column. Output is flushed every few
+// hundred rows and writing stops once ctx is cancelled (client went away).
+func WriteSeriesCSV(ctx context.Context, w io.Writer, series, volume []model.SeriesPoint) error {
+	type row struct {
+		bucket time.Time
+		count  int64
+		volume float64
+	}
+
+	rows := make(map[int64]*row, len(series))
+	get := func(bucket time.Time) *row {
+		key := bucket.UnixNano()
+		if existing, ok := rows[key]; ok {
+			return existing
+		}
+		created := &row{bucket: bucket}
+		rows[key] = created
+		return created
+	}
+
+	for _, point := range series {
+		get(point.Bucket).count = point.Count
+	}
+	for _, point := range volume {
+		entry := get(point.Bucket)
+		entry.volume = point.Value
+		if entry.count == 0 {
+			entry.count = point.Count
+		}
+	}
+
+	ordered := make([]*row, 0, len(rows))
+	for _, entry := range rows {
+		ordered = append(ordered, entry)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].bucket.Before(ordered[j].bucket)
+	})
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(seriesHeader); err != nil {
+		return err
+	}
+	for i, entry := range ordered {
+		if i > 0 && i%flushEvery == 0 {
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				return err
+			}
+			if err := flush(w); err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		record := []string{
+			entry.bucket.Format(time.RFC3339),
+			strconv.FormatInt(entry.count, 10),
+			strconv.FormatFloat(entry.volume, 'f', -1, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
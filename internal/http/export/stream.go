@@ -0,0 +1,72 @@
+package export
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+)
+
+// flushEvery is the number of rows written between flushes to the client.
+const flushEvery = 256
+
+// Stream writes an export straight to the response, optionally gzip
+// compressed. Flush pushes everything buffered so far to the client so large
+// exports are sent in chunks instead of being held in memory.
+type Stream struct {
+	w       io.Writer
+	gz      *gzip.Writer
+	flusher http.Flusher
+}
+
+func NewStream(w http.ResponseWriter, compress bool) *Stream {
+	stream := &Stream{w: w}
+	if flusher, ok := w.(http.Flusher); ok {
+		stream.flusher = flusher
+	}
+	if compress {
+		stream.gz = gzip.NewWriter(w)
+		stream.w = stream.gz
+	}
+	return stream
+}
+
+func (s *Stream) Write(p []byte) (int, error) {
+	return s.w.Write(p)
+}
+
+func (s *Stream) Flush() error {
+	if s.gz != nil {
+		if err := s.gz.Flush(); err != nil {
+			return err
+		}
+	}
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+	return nil
+}
+
+// Close terminates the gzip stream, if any, and flushes the remainder.
+func (s *Stream) Close() error {
+	if s.gz != nil {
+		if err := s.gz.Close(); err != nil {
+			return err
+		}
+	}
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+	return nil
+}
+
+type flusher interface {
+	Flush() error
+}
+
+// flush pushes buffered output to the client when w supports it.
+func flush(w io.Writer) error {
+	if f, ok := w.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package export
+
+import (
+	"context"
+
+	"github.com/xuri/excelize/v2"
+
+	"analytics-service/internal/model"
+)
+
+var contractHeader = []interface{}{
+	"Contract", "Contractor", "Start", "End",
+	"Budget total", "Total cost", "Budget progress",
+	"Minimal volume, m3", "Total volume, m3", "Volume progress",
+	"Status", "Result",
+}
+
+const (
+	currencyFormat = `#,##0.00 "₸"`
+	percentFormat  = "0.0%"
+	dateFormat     = "yyyy-mm-dd"
+	volumeFormat   = "#,##0.00"
+)
+
+// NewContractsXLSX builds the contract report as a workbook with Summary,
+// At Risk and Budget Issues sheets. Budget and cost columns are formatted as
+// currency, progress columns as percentages of the stored ratios. Rows go
+// through a StreamWriter, so the cell model is never held in memory, and
+// building stops once ctx is cancelled. The caller writes the workbook out
+// with WriteTo and closes it.
+func NewContractsXLSX(ctx context.Context, analytics *model.ContractAnalytics) (*excelize.File, error) {
+	file := excelize.NewFile()
+	if err := buildContractsXLSX(ctx, file, analytics); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+func buildContractsXLSX(ctx context.Context, file *excelize.File, analytics *model.ContractAnalytics) error {
+	styles, err := newContractStyles(file)
+	if err != nil {
+		return err
+	}
+
+	sheets := []struct {
+		name      string
+		contracts []model.ContractProgress
+	}{
+		{"Summary", analytics.Summary},
+		{"At Risk", analytics.AtRisk},
+		{"Budget Issues", analytics.BudgetIssues},
+	}
+	for i, sheet := range sheets {
+		if i == 0 {
+			if err := file.SetSheetName(file.GetSheetName(0), sheet.name); err != nil {
+				return err
+			}
+		} else if _, err := file.NewSheet(sheet.name); err != nil {
+			return err
+		}
+		if err := writeContractSheet(ctx, file, sheet.name, sheet.contracts, styles); err != nil {
+			return err
+		}
+	}
+	file.SetActiveSheet(0)
+	return nil
+}
+
+type contractStyles struct {
+	header, currency, percent, date, volume int
+}
+
+func newContractStyles(file *excelize.File) (contractStyles, error) {
+	var (
+		styles contractStyles
+		err    error
+	)
+	numFmt := func(format string) (int, error) {
+		return file.NewStyle(&excelize.Style{CustomNumFmt: &format})
+	}
+	if styles.header, err = file.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DDEBF7"}},
+	}); err != nil {
+		return styles, err
+	}
+	if styles.currency, err = numFmt(currencyFormat); err != nil {
+		return styles, err
+	}
+	if styles.percent, err = numFmt(percentFormat); err != nil {
+		return styles, err
+	}
+	if styles.date, err = numFmt(dateFormat); err != nil {
+		return styles, err
+	}
+	if styles.volume, err = numFmt(volumeFormat); err != nil {
+		return styles, err
+	}
+	return styles, nil
+}
+
+func writeContractSheet(ctx context.Context, file *excelize.File, sheet string, contracts []model.ContractProgress, styles contractStyles) error {
+	writer, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+	// Widths and panes must be set before the first row.
+	if err := writer.SetColWidth(1, 2, 32); err != nil {
+		return err
+	}
+	if err := writer.SetColWidth(3, len(contractHeader), 16); err != nil {
+		return err
+	}
+	if err := writer.SetPanes(&excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return err
+	}
+
+	if err := writer.SetRow("A1", contractHeader, excelize.RowOpts{StyleID: styles.header}); err != nil {
+		return err
+	}
+	for i, contract := range contracts {
+		if i%flushEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+		row := []interface{}{
+			contract.Name,
+			contract.ContractorName,
+			excelize.Cell{StyleID: styles.date, Value: contract.StartAt},
+			excelize.Cell{StyleID: styles.date, Value: contract.EndAt},
+			excelize.Cell{StyleID: styles.currency, Value: contract.BudgetTotal},
+			excelize.Cell{StyleID: styles.currency, Value: contract.TotalCost},
+			excelize.Cell{StyleID: styles.percent, Value: contract.BudgetProgress},
+			excelize.Cell{StyleID: styles.volume, Value: contract.MinimalVolume},
+			excelize.Cell{StyleID: styles.volume, Value: contract.TotalVolume},
+			excelize.Cell{StyleID: styles.percent, Value: contract.VolumeProgress},
+			contract.UIStatus,
+			contract.Result,
+		}
+		if err := writer.SetRow(cell, row); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
@@ -1,8 +1,13 @@
 package http
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,34 +15,74 @@ import (
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 
+	"analytics-service/internal/http/export"
 	"analytics-service/internal/http/middleware"
 	"analytics-service/internal/model"
 	"analytics-service/internal/service"
 )
 
+// statusClientClosedRequest is the non-standard status (nginx's 499) recorded
+// for requests the client abandoned before the response was written.
+const statusClientClosedRequest = 499
+
+// minSeriesInterval is the smallest accepted value of the interval param.
+const minSeriesInterval = 15 * time.Minute
+
+// maxConsistencyDays bounds the live trips scan of the consistency check.
+const maxConsistencyDays = 7
+
 type Handler struct {
-	analytics *service.AnalyticsService
-	log       zerolog.Logger
+	analytics       *service.AnalyticsService
+	log             zerolog.Logger
+	defaultPageSize int
+	maxPageSize     int
 }
 
-func NewHandler(analytics *service.AnalyticsService, log zerolog.Logger) *Handler {
-	return &Handler{analytics: analytics, log: log}
+func NewHandler(analytics *service.AnalyticsService, log zerolog.Logger, defaultPageSize, maxPageSize int) *Handler {
+	return &Handler{
+		analytics:       analytics,
+		log:             log,
+		defaultPageSize: defaultPageSize,
+		maxPageSize:     maxPageSize,
+	}
 }
 
 func (h *Handler) Register(r *gin.Engine, authMiddleware gin.HandlerFunc) {
 	protected := r.Group("/analytics")
 	protected.Use(authMiddleware)
 
+	protected.GET("/capabilities", h.getCapabilities)
 	protected.GET("/dashboard", h.getDashboard)
+	protected.GET("/map/geojson", h.getMapGeoJSON)
 	protected.GET("/trips", h.getTripAnalytics)
+	protected.GET("/trips/status-series", h.getTripStatusSeries)
+	protected.GET("/trips/peak-hours", h.getPeakHours)
+	protected.GET("/trips/heatmap", h.getTripHeatmap)
+	protected.GET("/trips/list", h.listTrips)
 	protected.GET("/trips/:id", h.getTripDetails)
+	protected.GET("/trips/:id/timeline", h.getTripTimeline)
 	protected.GET("/violations", h.getViolationAnalytics)
 	protected.GET("/performance", h.getPerformanceAnalytics)
+	protected.GET("/performance/volume-efficiency", h.getVolumeEfficiency)
 	protected.GET("/contracts", h.getContractAnalytics)
+	protected.GET("/contracts/progress", h.getContractProgressBars)
+	protected.GET("/contracts/series", h.getContractSeries)
+	protected.GET("/contractors/driver-count-series", h.getDriverCountSeries)
+	protected.GET("/contractors/rank-series", h.getContractorRankSeries)
+	protected.GET("/contractors/:id", h.getContractorDetails)
+	protected.GET("/kgu-comparison", h.getKguComparison)
 	protected.GET("/areas", h.listAreas)
+	protected.GET("/areas/idle", h.listIdleAreas)
 	protected.GET("/drivers", h.listDrivers)
 	protected.GET("/vehicles", h.listVehicles)
+	protected.GET("/vehicles/fill-distribution", h.getFillRateDistribution)
 	protected.GET("/technical", h.getTechnicalAnalytics)
+	protected.GET("/technical/coverage", h.getCameraCoverage)
+	protected.GET("/technical/offline-cameras", h.listOfflineCameras)
+	protected.GET("/quality", h.getTripQuality)
+	protected.GET("/cameras/:id/events", h.listCameraEvents)
+	protected.POST("/refresh", h.refreshViews)
+	protected.GET("/consistency", h.getConsistency)
 }
 
 func (h *Handler) getDashboard(c *gin.Context) {
@@ -47,25 +92,118 @@ func (h *Handler) getDashboard(c *gin.Context) {
 		return
 	}
 
-	rangeFilter := model.DateRange{}
-	if fromStr := strings.TrimSpace(c.Query("from")); fromStr != "" {
-		if parsed, err := time.Parse(time.RFC3339, fromStr); err == nil {
-			rangeFilter.From = parsed
-		}
+	rangeFilter, err := parseDateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
 	}
-	if toStr := strings.TrimSpace(c.Query("to")); toStr != "" {
-		if parsed, err := time.Parse(time.RFC3339, toStr); err == nil {
-			rangeFilter.To = parsed
+
+	bbox, err := parseBBox(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+
+	// The dashboard is cached and shared as one document in m³.
+	unit, err := parseVolumeUnit(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+	if rejectTonnes(c, unit) {
+		return
+	}
+
+	var cameraID *uuid.UUID
+	if cameraStr := strings.TrimSpace(c.Query("camera_id")); cameraStr != "" {
+		id, err := uuid.Parse(cameraStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("invalid camera_id"))
+			return
 		}
+		cameraID = &id
+	}
+
+	dashboard, err := h.analytics.GetDashboard(c.Request.Context(), principal, rangeFilter, bbox, cameraID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// The dashboard may be shared with concurrent requests, so meta is
+	// moved out of a copy rather than the original. The ETag leaves out the
+	// timestamps and generated_for, which ends at the request time for an
+	// open range; the query string in the tag already pins the range asked for.
+	data := *dashboard
+	data.Meta = nil
+	tagged := data
+	tagged.GeneratedFor = model.DateRange{}
+	h.conditionalJSONTagged(c, principal, gin.H{"data": data, "meta": dashboard.Meta}, tagged)
+}
+
+func (h *Handler) getCapabilities(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	capabilities, err := h.analytics.Capabilities(c.Request.Context(), principal)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(capabilities))
+}
+
+func (h *Handler) getKguComparison(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	filter, matches, ok := h.parseContractorFilter(c, principal)
+	if !ok {
+		return
+	}
+
+	items, err := h.analytics.GetKguComparison(c.Request.Context(), principal, filter)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, withVolumeUnit(filteredResponse(items, matches), filter.Unit))
+}
+
+func (h *Handler) getMapGeoJSON(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
 	}
 
-	dashboard, err := h.analytics.GetDashboard(c.Request.Context(), principal, rangeFilter)
+	rangeFilter, err := parseDateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+
+	bbox, err := parseBBox(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+
+	collection, err := h.analytics.GetMapGeoJSON(c.Request.Context(), principal, rangeFilter, bbox)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, successResponse(dashboard))
+	c.JSON(http.StatusOK, collection)
 }
 
 func (h *Handler) getTripAnalytics(c *gin.Context) {
@@ -75,15 +213,70 @@ func (h *Handler) getTripAnalytics(c *gin.Context) {
 		return
 	}
 
-	filter := h.parseAnalyticsFilter(c)
+	format := strings.ToLower(strings.TrimSpace(c.Query("format")))
+	if format != "" && format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, errorResponse("unsupported format"))
+		return
+	}
+
+	filter, matches, ok := h.parseContractorFilter(c, principal)
+	if !ok {
+		return
+	}
+
+	top, err := parseTop(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+	rankBy := strings.ToLower(strings.TrimSpace(c.Query("rank_by")))
+	if rankBy != "" && rankBy != model.RankByCount && rankBy != model.RankByVolume {
+		c.JSON(http.StatusBadRequest, errorResponse("rank_by must be count or volume"))
+		return
+	}
+
+	analytics, err := h.analytics.GetTripAnalytics(c.Request.Context(), principal, filter, top, rankBy)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if format == "csv" {
+		stream := startExport(c, "text/csv; charset=utf-8", exportFilename("trips", filter.Range, "csv"), true)
+		if err := export.WriteSeriesCSV(c.Request.Context(), stream, analytics.Series, analytics.VolumeSeries); err != nil {
+			h.requestLog(c).Error().Err(err).Msg("write trips csv")
+		}
+		if err := stream.Close(); err != nil {
+			h.requestLog(c).Error().Err(err).Msg("close trips csv stream")
+		}
+		return
+	}
+
+	h.conditionalJSON(c, principal, withVolumeUnit(filteredResponse(analytics, matches), filter.Unit))
+}
+
+func (h *Handler) getTripStatusSeries(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	filter, matches, ok := h.parseContractorFilter(c, principal)
+	if !ok {
+		return
+	}
+	if rejectTonnes(c, filter.Unit) {
+		return
+	}
 
-	analytics, err := h.analytics.GetTripAnalytics(c.Request.Context(), principal, filter)
+	series, err := h.analytics.GetTripStatusSeries(c.Request.Context(), principal, filter)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, successResponse(analytics))
+	c.JSON(http.StatusOK, filteredResponse(series, matches))
 }
 
 func (h *Handler) getTripDetails(c *gin.Context) {
@@ -109,200 +302,1153 @@ func (h *Handler) getTripDetails(c *gin.Context) {
 	c.JSON(http.StatusOK, successResponse(details))
 }
 
-func (h *Handler) getViolationAnalytics(c *gin.Context) {
+func (h *Handler) getContractorDetails(c *gin.Context) {
 	principal, ok := middleware.MustPrincipal(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
 		return
 	}
 
-	filter := h.parseAnalyticsFilter(c)
+	contractorID, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("invalid contractor id"))
+		return
+	}
+
+	filter, err := h.parseAnalyticsFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+	if filter.ContractorName != "" {
+		c.JSON(http.StatusBadRequest, errorResponse("contractor_name is not supported by this endpoint"))
+		return
+	}
+
+	top, err := parseTop(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
 
-	analytics, err := h.analytics.GetViolationAnalytics(c.Request.Context(), principal, filter)
+	details, err := h.analytics.GetContractorDetails(c.Request.Context(), principal, contractorID, filter, top)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, successResponse(analytics))
+	c.JSON(http.StatusOK, withVolumeUnit(successResponse(details), filter.Unit))
 }
 
-func (h *Handler) getPerformanceAnalytics(c *gin.Context) {
+func (h *Handler) getTripTimeline(c *gin.Context) {
 	principal, ok := middleware.MustPrincipal(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
 		return
 	}
 
-	filter := h.parseAnalyticsFilter(c)
+	tripID, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("invalid trip id"))
+		return
+	}
 
-	analytics, err := h.analytics.GetPerformanceAnalytics(c.Request.Context(), principal, filter)
+	timeline, err := h.analytics.GetTripTimeline(c.Request.Context(), principal, tripID)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, successResponse(analytics))
+	c.JSON(http.StatusOK, successResponse(timeline))
 }
 
-func (h *Handler) getContractAnalytics(c *gin.Context) {
+func (h *Handler) getViolationAnalytics(c *gin.Context) {
 	principal, ok := middleware.MustPrincipal(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
 		return
 	}
 
-	contracts, err := h.analytics.GetContractAnalytics(c.Request.Context(), principal)
+	filter, matches, ok := h.parseContractorFilter(c, principal)
+	if !ok {
+		return
+	}
+	if rejectTonnes(c, filter.Unit) {
+		return
+	}
+
+	top, err := parseTop(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+
+	leaders, err := parseLeaderOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+	leaders.Limit = top
+
+	analytics, err := h.analytics.GetViolationAnalytics(c.Request.Context(), principal, filter, leaders)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, successResponse(contracts))
+	h.conditionalJSON(c, principal, filteredResponse(analytics, matches))
 }
 
-func (h *Handler) listAreas(c *gin.Context) {
+func (h *Handler) getPerformanceAnalytics(c *gin.Context) {
 	principal, ok := middleware.MustPrincipal(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
 		return
 	}
 
-	filter := h.parseAnalyticsFilter(c)
-	areas, err := h.analytics.GetAreaAnalytics(c.Request.Context(), principal, filter)
+	filter, matches, ok := h.parseContractorFilter(c, principal)
+	if !ok {
+		return
+	}
+
+	sort := model.SortOrder{Key: strings.ToLower(strings.TrimSpace(c.Query("sort"))), Desc: true}
+	switch strings.ToLower(strings.TrimSpace(c.Query("order"))) {
+	case "", "desc":
+	case "asc":
+		sort.Desc = false
+	default:
+		c.JSON(http.StatusBadRequest, errorResponse("order must be asc or desc"))
+		return
+	}
+
+	top, err := parseTop(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+
+	analytics, err := h.analytics.GetPerformanceAnalytics(c.Request.Context(), principal, filter, sort, top)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, successResponse(areas))
+	h.conditionalJSON(c, principal, withVolumeUnit(filteredResponse(analytics, matches), filter.Unit))
 }
 
-func (h *Handler) listDrivers(c *gin.Context) {
+func (h *Handler) getVolumeEfficiency(c *gin.Context) {
 	principal, ok := middleware.MustPrincipal(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
 		return
 	}
 
-	filter := h.parseAnalyticsFilter(c)
-	drivers, err := h.analytics.GetDriverKPIs(c.Request.Context(), principal, filter)
+	filter, matches, ok := h.parseContractorFilter(c, principal)
+	if !ok {
+		return
+	}
+
+	contractors, err := h.analytics.GetVolumeEfficiency(c.Request.Context(), principal, filter)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, successResponse(drivers))
+	c.JSON(http.StatusOK, withVolumeUnit(filteredResponse(contractors, matches), filter.Unit))
 }
 
-func (h *Handler) listVehicles(c *gin.Context) {
+func (h *Handler) getContractAnalytics(c *gin.Context) {
 	principal, ok := middleware.MustPrincipal(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
 		return
 	}
 
-	filter := h.parseAnalyticsFilter(c)
-	vehicles, err := h.analytics.GetVehicleKPIs(c.Request.Context(), principal, filter)
+	format := strings.ToLower(strings.TrimSpace(c.Query("format")))
+	if format != "" && format != "json" && format != "xlsx" {
+		c.JSON(http.StatusBadRequest, errorResponse("unsupported format"))
+		return
+	}
+
+	filter := model.ContractFilter{
+		Status: strings.ToUpper(strings.TrimSpace(c.Query("status"))),
+		Result: strings.ToUpper(strings.TrimSpace(c.Query("result"))),
+	}
+	if filter.Status != "" && !model.IsContractStatus(filter.Status) {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Sprintf("invalid status: expected PLANNED, ACTIVE or EXPIRED, got %q", filter.Status)))
+		return
+	}
+	if filter.Result != "" && !model.IsContractResult(filter.Result) {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Sprintf("invalid result: expected SUCCESS, FAIL, OVER_BUDGET, ON_TRACK, BEHIND or NONE, got %q", filter.Result)))
+		return
+	}
+
+	contracts, err := h.analytics.GetContractAnalytics(c.Request.Context(), principal, filter)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, successResponse(vehicles))
+	if format == "xlsx" {
+		// The workbook is built before the headers go out, so a failure is
+		// still answered with an error status instead of a truncated file.
+		workbook, err := export.NewContractsXLSX(c.Request.Context(), contracts)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		defer workbook.Close()
+
+		// A workbook is already a zip archive, so it is sent uncompressed.
+		stream := startExport(c, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", exportFilename("contracts", model.DateRange{}, "xlsx"), false)
+		if _, err := workbook.WriteTo(stream); err != nil {
+			h.requestLog(c).Error().Err(err).Msg("write contracts xlsx")
+		}
+		if err := stream.Close(); err != nil {
+			h.requestLog(c).Error().Err(err).Msg("close contracts xlsx stream")
+		}
+		return
+	}
+
+	h.conditionalJSON(c, principal, successResponse(contracts))
 }
 
-func (h *Handler) getTechnicalAnalytics(c *gin.Context) {
+func (h *Handler) listAreas(c *gin.Context) {
 	principal, ok := middleware.MustPrincipal(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
 		return
 	}
 
-	rangeFilter := model.DateRange{}
-	if fromStr := strings.TrimSpace(c.Query("from")); fromStr != "" {
-		if parsed, err := time.Parse(time.RFC3339, fromStr); err == nil {
-			rangeFilter.From = parsed
-		}
-	}
-	if toStr := strings.TrimSpace(c.Query("to")); toStr != "" {
-		if parsed, err := time.Parse(time.RFC3339, toStr); err == nil {
-			rangeFilter.To = parsed
-		}
+	filter, matches, ok := h.parseContractorFilter(c, principal)
+	if !ok {
+		return
 	}
-
-	data, err := h.analytics.GetTechnicalAnalytics(c.Request.Context(), principal, rangeFilter)
+	areas, err := h.analytics.GetAreaAnalytics(c.Request.Context(), principal, filter)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, successResponse(data))
+	c.JSON(http.StatusOK, withVolumeUnit(filteredResponse(areas, matches), filter.Unit))
 }
 
-func (h *Handler) parseAnalyticsFilter(c *gin.Context) model.AnalyticsFilter {
-	filter := model.AnalyticsFilter{}
+func (h *Handler) listDrivers(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
 
-	if fromStr := strings.TrimSpace(c.Query("from")); fromStr != "" {
-		if parsed, err := time.Parse(time.RFC3339, fromStr); err == nil {
-			filter.Range.From = parsed
-		}
+	filter, matches, ok := h.parseContractorFilter(c, principal)
+	if !ok {
+		return
 	}
-	if toStr := strings.TrimSpace(c.Query("to")); toStr != "" {
-		if parsed, err := time.Parse(time.RFC3339, toStr); err == nil {
-			filter.Range.To = parsed
-		}
+	page, err := h.parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+	drivers, err := h.analytics.GetDriverKPIs(c.Request.Context(), principal, filter, page)
+	if err != nil {
+		h.handleError(c, err)
+		return
 	}
 
-	if contractorStr := strings.TrimSpace(c.Query("contractor_id")); contractorStr != "" {
-		if id, err := uuid.Parse(contractorStr); err == nil {
-			filter.ContractorID = &id
-		}
+	setPageHeaders(c, drivers.Limit, drivers.Offset, drivers.Total)
+	setListMeta(c, matches, filter.Unit)
+	c.JSON(http.StatusOK, drivers.Items)
+}
+
+func (h *Handler) listTrips(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
 	}
-	if driverStr := strings.TrimSpace(c.Query("driver_id")); driverStr != "" {
-		if id, err := uuid.Parse(driverStr); err == nil {
-			filter.DriverID = &id
-		}
+
+	filter, matches, ok := h.parseContractorFilter(c, principal)
+	if !ok {
+		return
 	}
-	if polygonStr := strings.TrimSpace(c.Query("polygon_id")); polygonStr != "" {
-		if id, err := uuid.Parse(polygonStr); err == nil {
-			filter.PolygonID = &id
+	page, err := h.parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+	if lastStr := strings.TrimSpace(c.Query("last_n")); lastStr != "" {
+		lastN, err := strconv.Atoi(lastStr)
+		if err != nil || lastN <= 0 || lastN > h.maxPageSize {
+			c.JSON(http.StatusBadRequest, errorResponse(fmt.Sprintf("last_n must be an integer between 1 and %d", h.maxPageSize)))
+			return
 		}
+		page.LastN = lastN
 	}
-	if cameraStr := strings.TrimSpace(c.Query("camera_id")); cameraStr != "" {
-		if id, err := uuid.Parse(cameraStr); err == nil {
-			filter.CameraID = &id
+	if cursorStr := strings.TrimSpace(c.Query("cursor")); cursorStr != "" {
+		if page.LastN > 0 || page.Offset > 0 {
+			c.JSON(http.StatusBadRequest, errorResponse("cursor cannot be combined with offset or last_n"))
+			return
+		}
+		cursor, err := model.ParseTripCursor(cursorStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("invalid cursor: pass next_cursor from a previous page unchanged"))
+			return
 		}
+		page.After = &cursor
 	}
-
-	switch strings.ToLower(strings.TrimSpace(c.Query("group_by"))) {
-	case "week":
-		filter.GroupBy = model.GroupByWeek
-	case "month":
-		filter.GroupBy = model.GroupByMonth
-	default:
-		filter.GroupBy = model.GroupByDay
+	trips, err := h.analytics.ListTrips(c.Request.Context(), principal, filter, page)
+	if err != nil {
+		h.handleError(c, err)
+		return
 	}
 
-	return filter
-}
-
-func (h *Handler) handleError(c *gin.Context, err error) {
 	switch {
-	case errors.Is(err, service.ErrPermissionDenied):
-		c.JSON(http.StatusForbidden, errorResponse(err.Error()))
-	case errors.Is(err, service.ErrNotFound):
-		c.JSON(http.StatusNotFound, errorResponse(err.Error()))
+	case page.LastN > 0:
+		// The most recent trips are not a page, so there is nothing to link.
+		c.Header(totalCountHeader, strconv.FormatInt(trips.Total, 10))
+	case page.After != nil:
+		setCursorHeaders(c, trips.Total, trips.NextCursor)
 	default:
-		h.log.Error().Err(err).Str("error_type", "unhandled").Msg("handler error")
-		c.JSON(http.StatusInternalServerError, errorResponse("internal error"))
+		setPageHeaders(c, trips.Limit, trips.Offset, trips.Total)
 	}
+	setListMeta(c, matches, filter.Unit)
+	c.JSON(http.StatusOK, trips.Items)
 }
 
-func successResponse(data interface{}) gin.H {
+func (h *Handler) listVehicles(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	filter, matches, ok := h.parseContractorFilter(c, principal)
+	if !ok {
+		return
+	}
+	if rejectTonnes(c, filter.Unit) {
+		return
+	}
+	vehicles, err := h.analytics.GetVehicleKPIs(c.Request.Context(), principal, filter)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, filteredResponse(vehicles, matches))
+}
+
+func (h *Handler) getTechnicalAnalytics(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	rangeFilter, err := parseDateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+
+	data, err := h.analytics.GetTechnicalAnalytics(c.Request.Context(), principal, rangeFilter)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.conditionalJSON(c, principal, successResponse(data))
+}
+
+func (h *Handler) getTripQuality(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	rangeFilter, err := parseDateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+
+	quality, err := h.analytics.GetTripQuality(c.Request.Context(), principal, rangeFilter)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(quality))
+}
+
+func (h *Handler) getCameraCoverage(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	coverage, err := h.analytics.GetCameraCoverage(c.Request.Context(), principal)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(coverage))
+}
+
+func (h *Handler) listOfflineCameras(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	rangeFilter, err := parseDateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+
+	cameras, err := h.analytics.GetOfflineCameras(c.Request.Context(), principal, rangeFilter)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(cameras))
+}
+
+func (h *Handler) refreshViews(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	results, err := h.analytics.RefreshMaterializedViews(c.Request.Context(), principal)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != nil {
+			failed++
+		}
+	}
+	h.requestLog(c).Info().
+		Str("user_id", principal.UserID.String()).
+		Int("views", len(results)).
+		Int("failed", failed).
+		Msg("materialized view refresh requested")
+
+	c.JSON(http.StatusOK, successResponse(results))
+}
+
+func (h *Handler) getConsistency(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	days := 1
+	if daysStr := strings.TrimSpace(c.Query("days")); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed <= 0 || parsed > maxConsistencyDays {
+			c.JSON(http.StatusBadRequest, errorResponse(fmt.Sprintf("days must be an integer between 1 and %d", maxConsistencyDays)))
+			return
+		}
+		days = parsed
+	}
+
+	report, err := h.analytics.GetConsistency(c.Request.Context(), principal, days)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(report))
+}
+
+// parseContractorFilter parses the analytics filter and resolves its
+// contractor_name, if any, to contractor IDs within the principal's scope.
+// It writes the error response itself and reports whether to continue.
+func (h *Handler) parseContractorFilter(c *gin.Context, principal model.Principal) (model.AnalyticsFilter, []model.ContractorMatch, bool) {
+	filter, err := h.parseAnalyticsFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return filter, nil, false
+	}
+	filter, matches, err := h.analytics.ResolveContractorName(c.Request.Context(), principal, filter)
+	if err != nil {
+		h.handleError(c, err)
+		return filter, nil, false
+	}
+	return filter, matches, true
+}
+
+func (h *Handler) parseAnalyticsFilter(c *gin.Context) (model.AnalyticsFilter, error) {
+	rng, err := parseDateRange(c)
+	if err != nil {
+		return model.AnalyticsFilter{}, err
+	}
+	filter := model.AnalyticsFilter{Range: rng}
+
+	// contractor_id may be repeated or comma separated; the lists are
+	// merged and duplicates dropped. Empty parts are skipped, but a value
+	// that is not a UUID rejects the request: ignoring it would widen the
+	// filter to every contractor in scope.
+	var contractorIDs []uuid.UUID
+	for _, raw := range c.QueryArray("contractor_id") {
+		for _, part := range strings.Split(raw, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			id, err := uuid.Parse(part)
+			if err != nil {
+				return model.AnalyticsFilter{}, fmt.Errorf("invalid contractor_id: %q", part)
+			}
+			if !slices.Contains(contractorIDs, id) {
+				contractorIDs = append(contractorIDs, id)
+			}
+		}
+	}
+	switch len(contractorIDs) {
+	case 0:
+	case 1:
+		filter.ContractorID = &contractorIDs[0]
+	default:
+		filter.ContractorIDs = contractorIDs
+	}
+	filter.ContractorName = strings.TrimSpace(c.Query("contractor_name"))
+	if driverStr := strings.TrimSpace(c.Query("driver_id")); driverStr != "" {
+		if id, err := uuid.Parse(driverStr); err == nil {
+			filter.DriverID = &id
+		}
+	}
+	if vehicleStr := strings.TrimSpace(c.Query("vehicle_id")); vehicleStr != "" {
+		if id, err := uuid.Parse(vehicleStr); err == nil {
+			filter.VehicleID = &id
+		}
+	}
+	if polygonStr := strings.TrimSpace(c.Query("polygon_id")); polygonStr != "" {
+		if id, err := uuid.Parse(polygonStr); err == nil {
+			filter.PolygonID = &id
+		}
+	}
+	if cameraStr := strings.TrimSpace(c.Query("camera_id")); cameraStr != "" {
+		if id, err := uuid.Parse(cameraStr); err == nil {
+			filter.CameraID = &id
+		}
+	}
+	if orgStr := strings.TrimSpace(c.Query("created_by_org_id")); orgStr != "" {
+		id, err := uuid.Parse(orgStr)
+		if err != nil {
+			return model.AnalyticsFilter{}, errors.New("invalid created_by_org_id")
+		}
+		filter.CreatedByOrgID = &id
+	}
+
+	switch strings.ToLower(strings.TrimSpace(c.Query("group_by"))) {
+	case "hour":
+		filter.GroupBy = model.GroupByHour
+	case "week":
+		filter.GroupBy = model.GroupByWeek
+	case "month":
+		filter.GroupBy = model.GroupByMonth
+	default:
+		filter.GroupBy = model.GroupByDay
+	}
+
+	switch strings.ToLower(strings.TrimSpace(c.Query("group_by_entity"))) {
+	case "contractor":
+		filter.GroupByEntity = model.GroupByEntityContractor
+	case "driver":
+		filter.GroupByEntity = model.GroupByEntityDriver
+	case "area":
+		filter.GroupByEntity = model.GroupByEntityArea
+	}
+
+	for _, raw := range c.QueryArray("status") {
+		for _, status := range strings.Split(raw, ",") {
+			status = strings.ToUpper(strings.TrimSpace(status))
+			if status == "" {
+				continue
+			}
+			if !model.IsTripStatus(status) {
+				return model.AnalyticsFilter{}, fmt.Errorf("invalid status: unknown trip status %q", status)
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	for _, raw := range c.QueryArray("severity") {
+		for _, severity := range strings.Split(raw, ",") {
+			severity = strings.ToUpper(strings.TrimSpace(severity))
+			if severity == "" {
+				continue
+			}
+			if !model.IsSeverity(severity) {
+				return model.AnalyticsFilter{}, fmt.Errorf("invalid severity: expected HIGH, MEDIUM or LOW, got %q", severity)
+			}
+			filter.Severities = append(filter.Severities, severity)
+		}
+	}
+
+	if tzStr := strings.TrimSpace(c.Query("tz")); tzStr != "" {
+		loc, err := time.LoadLocation(tzStr)
+		if err != nil || tzStr == "Local" {
+			return model.AnalyticsFilter{}, fmt.Errorf("invalid tz: unknown IANA time zone %q", tzStr)
+		}
+		filter.TZ = loc
+	}
+
+	if intervalStr := strings.TrimSpace(c.Query("interval")); intervalStr != "" {
+		interval, err := time.ParseDuration(intervalStr)
+		if err != nil || interval < minSeriesInterval || interval%time.Minute != 0 {
+			return model.AnalyticsFilter{}, fmt.Errorf("invalid interval: expected a whole number of minutes of at least %s, e.g. 6h", minSeriesInterval)
+		}
+		filter.Interval = interval
+	}
+
+	unit, err := parseVolumeUnit(c)
+	if err != nil {
+		return model.AnalyticsFilter{}, err
+	}
+	filter.Unit = unit
+
+	return filter, nil
+}
+
+// parseVolumeUnit reads the unit query param; it defaults to m³.
+func parseVolumeUnit(c *gin.Context) (model.VolumeUnit, error) {
+	switch unit := model.VolumeUnit(strings.ToLower(strings.TrimSpace(c.Query("unit")))); unit {
+	case "", model.VolumeUnitM3:
+		return model.VolumeUnitM3, nil
+	case model.VolumeUnitTonnes:
+		return unit, nil
+	default:
+		return "", fmt.Errorf("invalid unit: expected m3 or tonnes, got %q", unit)
+	}
+}
+
+// rejectTonnes answers 400 for unit=tonnes on endpoints that do not convert
+// volumes, so a requested unit is never silently ignored.
+func rejectTonnes(c *gin.Context, unit model.VolumeUnit) bool {
+	if unit != model.VolumeUnitTonnes {
+		return false
+	}
+	c.JSON(http.StatusBadRequest, errorResponse("unit=tonnes is not supported by this endpoint"))
+	return true
+}
+
+// parseDateRange reads the from/to query params. Both accept RFC3339 or a
+// plain YYYY-MM-DD date, which expands to the start (from) or end (to) of
+// that day in UTC. Missing params stay zero so the service applies defaults.
+func parseDateRange(c *gin.Context) (model.DateRange, error) {
+	var rng model.DateRange
+	if fromStr := strings.TrimSpace(c.Query("from")); fromStr != "" {
+		parsed, err := parseDateParam(fromStr, false)
+		if err != nil {
+			return model.DateRange{}, fmt.Errorf("invalid from: expected RFC3339 or YYYY-MM-DD, got %q", fromStr)
+		}
+		rng.From = parsed
+	}
+	if toStr := strings.TrimSpace(c.Query("to")); toStr != "" {
+		parsed, err := parseDateParam(toStr, true)
+		if err != nil {
+			return model.DateRange{}, fmt.Errorf("invalid to: expected RFC3339 or YYYY-MM-DD, got %q", toStr)
+		}
+		rng.To = parsed
+	}
+	if strictStr := strings.TrimSpace(c.Query("strict_range")); strictStr != "" {
+		strict, err := strconv.ParseBool(strictStr)
+		if err != nil {
+			return model.DateRange{}, errors.New("strict_range must be true or false")
+		}
+		rng.Strict = strict
+	}
+	return rng, nil
+}
+
+// parseBBox reads bbox=minLon,minLat,maxLon,maxLat. A missing param yields
+// nil, i.e. no spatial filter.
+func parseBBox(c *gin.Context) (*model.BBox, error) {
+	raw := strings.TrimSpace(c.Query("bbox"))
+	if raw == "" {
+		return nil, nil
+	}
+	parts := strings.Split(raw, ",")
+	if len(parts) != 4 {
+		return nil, errors.New("bbox must be minLon,minLat,maxLon,maxLat")
+	}
+	values := make([]float64, len(parts))
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, fmt.Errorf("invalid bbox coordinate %q", part)
+		}
+		values[i] = value
+	}
+	bbox := &model.BBox{MinLon: values[0], MinLat: values[1], MaxLon: values[2], MaxLat: values[3]}
+	if bbox.MinLon < -180 || bbox.MaxLon > 180 || bbox.MinLat < -90 || bbox.MaxLat > 90 {
+		return nil, errors.New("bbox longitudes must be within -180..180 and latitudes within -90..90")
+	}
+	if bbox.MinLon >= bbox.MaxLon || bbox.MinLat >= bbox.MaxLat {
+		return nil, errors.New("bbox min coordinates must be less than max coordinates")
+	}
+	return bbox, nil
+}
+
+func parseDateParam(value string, endOfDay bool) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	day, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		return day.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	return day, nil
+}
+
+func (h *Handler) getDriverCountSeries(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	filter, matches, ok := h.parseContractorFilter(c, principal)
+	if !ok {
+		return
+	}
+	if rejectTonnes(c, filter.Unit) {
+		return
+	}
+
+	series, err := h.analytics.GetDriverCountSeries(c.Request.Context(), principal, filter)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, filteredResponse(series, matches))
+}
+
+func (h *Handler) getContractProgressBars(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	bars, err := h.analytics.GetContractProgressBars(c.Request.Context(), principal)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(bars))
+}
+
+func (h *Handler) getContractSeries(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	contractID, err := uuid.Parse(strings.TrimSpace(c.Query("contract_id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("contract_id is required"))
+		return
+	}
+
+	filter, err := h.parseAnalyticsFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+	if filter.ContractorName != "" {
+		c.JSON(http.StatusBadRequest, errorResponse("contractor_name is not supported by this endpoint"))
+		return
+	}
+
+	series, err := h.analytics.GetContractSeries(c.Request.Context(), principal, contractID, filter)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, withVolumeUnit(successResponse(series), filter.Unit))
+}
+
+func (h *Handler) getContractorRankSeries(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	filter, err := h.parseAnalyticsFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+	if filter.ContractorName != "" {
+		c.JSON(http.StatusBadRequest, errorResponse("contractor_name is not supported by this endpoint"))
+		return
+	}
+
+	byVolume := true
+	switch strings.ToLower(strings.TrimSpace(c.Query("metric"))) {
+	case "", "volume":
+	case "trips":
+		byVolume = false
+	default:
+		c.JSON(http.StatusBadRequest, errorResponse("metric must be volume or trips"))
+		return
+	}
+	if !byVolume && rejectTonnes(c, filter.Unit) {
+		return
+	}
+
+	series, err := h.analytics.GetContractorRankSeries(c.Request.Context(), principal, filter, byVolume)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, withVolumeUnit(successResponse(series), filter.Unit))
+}
+
+func (h *Handler) getPeakHours(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	filter, matches, ok := h.parseContractorFilter(c, principal)
+	if !ok {
+		return
+	}
+	if rejectTonnes(c, filter.Unit) {
+		return
+	}
+
+	limit, err := parseLimit(c, 5, 24)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+
+	hours, err := h.analytics.GetPeakHours(c.Request.Context(), principal, filter, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, filteredResponse(hours, matches))
+}
+
+func (h *Handler) getTripHeatmap(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	filter, matches, ok := h.parseContractorFilter(c, principal)
+	if !ok {
+		return
+	}
+	if rejectTonnes(c, filter.Unit) {
+		return
+	}
+
+	heatmap, err := h.analytics.GetTripHeatmap(c.Request.Context(), principal, filter)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, filteredResponse(heatmap, matches))
+}
+
+func (h *Handler) getFillRateDistribution(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	filter, matches, ok := h.parseContractorFilter(c, principal)
+	if !ok {
+		return
+	}
+	if rejectTonnes(c, filter.Unit) {
+		return
+	}
+
+	distribution, err := h.analytics.GetFillRateDistribution(c.Request.Context(), principal, filter)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, filteredResponse(distribution, matches))
+}
+
+func (h *Handler) listIdleAreas(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	filter, matches, ok := h.parseContractorFilter(c, principal)
+	if !ok {
+		return
+	}
+	limit, err := parseLimit(c, 10, h.maxPageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+
+	areas, err := h.analytics.GetIdleAreas(c.Request.Context(), principal, filter, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, withVolumeUnit(filteredResponse(areas, matches), filter.Unit))
+}
+
+func (h *Handler) listCameraEvents(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errorResponse("missing principal"))
+		return
+	}
+
+	cameraID, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("invalid camera id"))
+		return
+	}
+	rng, err := parseDateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+	page, err := h.parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		return
+	}
+
+	events, err := h.analytics.GetCameraEvents(c.Request.Context(), principal, cameraID, rng, page)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	setPageHeaders(c, events.Limit, events.Offset, events.Total)
+	c.JSON(http.StatusOK, events.Items)
+}
+
+func (h *Handler) readyz(c *gin.Context) {
+	readiness := h.analytics.Readiness(c.Request.Context())
+	if readiness.Status == "UNAVAILABLE" {
+		h.requestLog(c).Warn().Str("failed", readiness.Failed).Msg("readiness check failed")
+		c.JSON(http.StatusServiceUnavailable, readiness)
+		return
+	}
+	c.JSON(http.StatusOK, readiness)
+}
+
+// parseLimit reads the limit query param for top-N endpoints.
+func parseLimit(c *gin.Context, defaultLimit, maxLimit int) (int, error) {
+	limitStr := strings.TrimSpace(c.Query("limit"))
+	if limitStr == "" {
+		return defaultLimit, nil
+	}
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 || limit > maxLimit {
+		return 0, fmt.Errorf("limit must be an integer between 1 and %d", maxLimit)
+	}
+	return limit, nil
+}
+
+// parseTop reads the leader list size. Zero means the endpoint default;
+// values above the maximum are clamped by the service.
+func parseTop(c *gin.Context) (int, error) {
+	topStr := strings.TrimSpace(c.Query("top"))
+	if topStr == "" {
+		return 0, nil
+	}
+	top, err := strconv.Atoi(topStr)
+	if err != nil || top <= 0 {
+		return 0, errors.New("top must be a positive integer")
+	}
+	return top, nil
+}
+
+// parseLeaderOptions reads the violation leader thresholds and ordering.
+func parseLeaderOptions(c *gin.Context) (model.LeaderOptions, error) {
+	var opts model.LeaderOptions
+	if raw := strings.TrimSpace(c.Query("leader_min_count")); raw != "" {
+		count, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || count < 0 {
+			return opts, errors.New("leader_min_count must be a non-negative integer")
+		}
+		opts.MinCount = count
+	}
+	if raw := strings.TrimSpace(c.Query("leader_min_share")); raw != "" {
+		share, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(share) || share < 0 || share > 1 {
+			return opts, errors.New("leader_min_share must be a number between 0 and 1")
+		}
+		opts.MinShare = share
+	}
+	switch order := strings.ToLower(strings.TrimSpace(c.Query("leader_order"))); order {
+	case "", model.LeaderOrderCount:
+		opts.OrderBy = model.LeaderOrderCount
+	case model.LeaderOrderShare:
+		opts.OrderBy = model.LeaderOrderShare
+	default:
+		return opts, errors.New("leader_order must be count or share")
+	}
+	switch by := strings.ToLower(strings.TrimSpace(c.Query("by"))); by {
+	case "", model.LeaderByCount:
+		opts.By = model.LeaderByCount
+	case model.LeaderByRate:
+		if opts.OrderBy == model.LeaderOrderShare {
+			return opts, errors.New("leader_order=share cannot be combined with by=rate")
+		}
+		opts.By = model.LeaderByRate
+	default:
+		return opts, errors.New("by must be count or rate")
+	}
+	return opts, nil
+}
+
+func (h *Handler) parsePagination(c *gin.Context) (model.Pagination, error) {
+	page := model.Pagination{Limit: h.defaultPageSize}
+
+	if limitStr := strings.TrimSpace(c.Query("limit")); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > h.maxPageSize {
+			return model.Pagination{}, fmt.Errorf("limit must be an integer between 1 and %d", h.maxPageSize)
+		}
+		page.Limit = limit
+	}
+	if offsetStr := strings.TrimSpace(c.Query("offset")); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return model.Pagination{}, errors.New("offset must be a non-negative integer")
+		}
+		page.Offset = offset
+	}
+
+	return page, nil
+}
+
+// requestLog returns the request scoped logger set up by middleware.RequestID,
+// falling back to h.log outside of it.
+func (h *Handler) requestLog(c *gin.Context) *zerolog.Logger {
+	if log := zerolog.Ctx(c.Request.Context()); log.GetLevel() != zerolog.Disabled {
+		return log
+	}
+	return &h.log
+}
+
+func (h *Handler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrPermissionDenied):
+		c.JSON(http.StatusForbidden, errorResponse(err.Error()))
+	case errors.Is(err, service.ErrNotFound):
+		c.JSON(http.StatusNotFound, errorResponse(err.Error()))
+	case errors.Is(err, service.ErrInvalidFilter):
+		c.JSON(http.StatusUnprocessableEntity, errorResponse(err.Error()))
+	case errors.Is(err, service.ErrInvalidInterval), errors.Is(err, service.ErrContractorRequired), errors.Is(err, service.ErrInvalidSort), errors.Is(err, service.ErrTooManyMatches), errors.Is(err, service.ErrRangeTooLarge), errors.Is(err, service.ErrInvalidOrgFilter):
+		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+	case errors.Is(c.Request.Context().Err(), context.Canceled):
+		// The client is gone, so nobody reads a body; the status only ends
+		// up in metrics and access logs.
+		h.requestLog(c).Debug().Err(err).Str("path", c.FullPath()).Msg("client closed request")
+		c.AbortWithStatus(statusClientClosedRequest)
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(c.Request.Context().Err(), context.DeadlineExceeded):
+		h.requestLog(c).Warn().Err(err).Str("path", c.FullPath()).Msg("analytics query timed out")
+		c.JSON(http.StatusGatewayTimeout, errorResponse("query timed out, narrow the date range or filters"))
+	default:
+		h.requestLog(c).Error().Err(err).Str("error_type", "unhandled").Msg("handler error")
+		c.JSON(http.StatusInternalServerError, errorResponse("internal error"))
+	}
+}
+
+// startExport writes the headers of a chunked file download and returns the
+// stream to write it to. A compressible export is gzip compressed when the
+// client accepts it; formats that are archives already pass false.
+func startExport(c *gin.Context, contentType, filename string, compressible bool) *export.Stream {
+	compress := compressible && strings.Contains(c.GetHeader("Accept-Encoding"), "gzip")
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Transfer-Encoding", "chunked")
+	if compressible {
+		c.Header("Vary", "Accept-Encoding")
+	}
+	if compress {
+		c.Header("Content-Encoding", "gzip")
+	}
+	c.Status(http.StatusOK)
+
+	return export.NewStream(c.Writer, compress)
+}
+
+func exportFilename(prefix string, rng model.DateRange, ext string) string {
+	const layout = "20060102"
+	if rng.From.IsZero() || rng.To.IsZero() {
+		return fmt.Sprintf("%s_%s.%s", prefix, time.Now().UTC().Format(layout), ext)
+	}
+	return fmt.Sprintf("%s_%s_%s.%s", prefix, rng.From.UTC().Format(layout), rng.To.UTC().Format(layout), ext)
+}
+
+func successResponse(data interface{}) gin.H {
 	return gin.H{"data": data}
 }
 
+// filteredResponse adds the contractors a contractor_name filter resolved
+// to as meta, so clients can tell what was matched.
+func filteredResponse(data interface{}, matches []model.ContractorMatch) gin.H {
+	if matches == nil {
+		return successResponse(data)
+	}
+	return gin.H{"data": data, "meta": gin.H{"contractor_matches": matches}}
+}
+
+// withVolumeUnit records in meta the unit the volumes of body are in.
+func withVolumeUnit(body gin.H, unit model.VolumeUnit) gin.H {
+	meta, ok := body["meta"].(gin.H)
+	if !ok {
+		meta = gin.H{}
+		body["meta"] = meta
+	}
+	meta["volume_unit"] = unit
+	return body
+}
+
 func errorResponse(message string) gin.H {
 	return gin.H{"error": message}
 }
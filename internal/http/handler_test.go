@@ -0,0 +1,183 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"analytics-service/internal/http/middleware"
+	"analytics-service/internal/model"
+	"analytics-service/internal/service"
+)
+
+func TestHandleErrorMapsInvalidFilterTo422(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewHandler(nil, zerolog.Nop(), 20, 100)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/analytics/trips", nil)
+
+	h.handleError(c, fmt.Errorf("%w: camera_id cannot be combined with group_by_entity", service.ErrInvalidFilter))
+
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("got status %d, want %d", recorder.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestUnitTonnesRejectedWithoutVolumes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for _, tc := range []struct {
+		query string
+		want  int
+	}{
+		{"", http.StatusOK},
+		{"?unit=m3", http.StatusOK},
+		{"?unit=TONNES", http.StatusBadRequest},
+	} {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = httptest.NewRequest(http.MethodGet, "/analytics/vehicles"+tc.query, nil)
+
+		unit, err := parseVolumeUnit(c)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.query, err)
+		}
+		if !rejectTonnes(c, unit) {
+			c.Status(http.StatusOK)
+		}
+		if recorder.Code != tc.want {
+			t.Errorf("%q: got status %d, want %d", tc.query, recorder.Code, tc.want)
+		}
+	}
+}
+
+func TestParseAnalyticsFilterContractorIDs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewHandler(nil, zerolog.Nop(), 20, 100)
+	a := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	b := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	d := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+
+	for _, tc := range []struct {
+		name    string
+		query   string
+		want    []uuid.UUID
+		wantErr bool
+	}{
+		{name: "none", query: ""},
+		{name: "single", query: "contractor_id=" + a.String(), want: []uuid.UUID{a}},
+		{name: "repeated", query: "contractor_id=" + a.String() + "&contractor_id=" + b.String(), want: []uuid.UUID{a, b}},
+		{name: "comma separated", query: "contractor_id=" + a.String() + ",%20" + b.String(), want: []uuid.UUID{a, b}},
+		{name: "merged without duplicates", query: "contractor_id=" + a.String() + "," + b.String() + "&contractor_id=" + b.String() + "," + d.String(), want: []uuid.UUID{a, b, d}},
+		{name: "empty parts skipped", query: "contractor_id=," + a.String() + ",", want: []uuid.UUID{a}},
+		{name: "invalid", query: "contractor_id=not-a-uuid", wantErr: true},
+		{name: "invalid among valid", query: "contractor_id=" + a.String() + ",nope&contractor_id=" + b.String(), wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/analytics/trips?"+tc.query, nil)
+
+			filter, err := h.parseAnalyticsFilter(c)
+			if tc.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "contractor_id") {
+					t.Fatalf("got error %v, want an invalid contractor_id error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []uuid.UUID
+			switch {
+			case filter.ContractorID != nil:
+				got = []uuid.UUID{*filter.ContractorID}
+			case filter.ContractorIDs != nil:
+				got = filter.ContractorIDs
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetListMeta(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	a := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	b := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+
+	for _, tc := range []struct {
+		name        string
+		matches     []model.ContractorMatch
+		wantMatches []string
+	}{
+		{name: "no contractor_name", matches: nil, wantMatches: nil},
+		{name: "nothing matched", matches: []model.ContractorMatch{}, wantMatches: []string{""}},
+		{name: "matched", matches: []model.ContractorMatch{{ID: a, Name: "Альфа"}, {ID: b, Name: "Бета"}}, wantMatches: []string{a.String() + "," + b.String()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+
+			setListMeta(c, tc.matches, model.VolumeUnitTonnes)
+
+			if got := recorder.Header().Get(volumeUnitHeader); got != "tonnes" {
+				t.Errorf("got %s %q, want tonnes", volumeUnitHeader, got)
+			}
+			if got := recorder.Header().Values(contractorMatchesHeader); !slices.Equal(got, tc.wantMatches) {
+				t.Errorf("got %s %q, want %q", contractorMatchesHeader, got, tc.wantMatches)
+			}
+		})
+	}
+}
+
+func TestGzipETagIsEncodingSpecific(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewHandler(nil, zerolog.Nop(), 20, 100)
+	router := gin.New()
+	router.Use(middleware.Gzip(0))
+	router.GET("/report", func(c *gin.Context) {
+		h.conditionalJSON(c, model.Principal{}, successResponse("payload"))
+	})
+	get := func(acceptEncoding, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/report", nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	identity := get("", "")
+	compressed := get("gzip", "")
+	for name, recorder := range map[string]*httptest.ResponseRecorder{"identity": identity, "gzip": compressed} {
+		if !slices.Contains(recorder.Header().Values("Vary"), "Accept-Encoding") {
+			t.Errorf("%s: Vary %q lacks Accept-Encoding", name, recorder.Header().Values("Vary"))
+		}
+	}
+	identityTag, gzipTag := identity.Header().Get("ETag"), compressed.Header().Get("ETag")
+	if compressed.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("response was not compressed")
+	}
+	if want := strings.TrimSuffix(identityTag, `"`) + middleware.GzipETagSuffix + `"`; gzipTag != want {
+		t.Errorf("got gzip ETag %s, want %s", gzipTag, want)
+	}
+
+	for _, tag := range []string{identityTag, gzipTag} {
+		if recorder := get("gzip", tag); recorder.Code != http.StatusNotModified {
+			t.Errorf("If-None-Match %s: got status %d, want %d", tag, recorder.Code, http.StatusNotModified)
+		}
+	}
+}
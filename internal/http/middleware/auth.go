@@ -17,7 +17,9 @@ const (
 	bearerPrefix = "Bearer"
 )
 
-func Auth(parser *auth.Parser) gin.HandlerFunc {
+// Auth validates the bearer token and stores the principal. With
+// requireDriverID set, driver tokens without a driver_id claim are rejected.
+func Auth(parser *auth.Parser, requireDriverID bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		raw := c.GetHeader(authHeader)
 		if raw == "" {
@@ -40,9 +42,14 @@ func Auth(parser *auth.Parser) gin.HandlerFunc {
 		principal := model.Principal{
 			UserID:   claims.UserID,
 			OrgID:    claims.OrgID,
+			OrgIDs:   claims.OrgIDs,
 			Role:     claims.Role,
 			DriverID: claims.DriverID,
 		}
+		if requireDriverID && !principal.HasDriverIdentity() {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "driver token missing driver_id"})
+			return
+		}
 
 		c.Set(claimsKey, claims)
 		c.Set(principalKey, principal)
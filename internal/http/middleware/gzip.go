@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GzipETagSuffix is appended inside the quotes of a strong ETag when its
+// response is compressed, so the gzip and identity bytes never share a tag.
+// Conditional request checks strip it again to compare the underlying tag.
+const GzipETagSuffix = "-gzip"
+
+// Gzip compresses responses of at least minSize bytes for clients that
+// accept gzip. Smaller bodies are sent as is, and responses that set their
+// own Content-Encoding or Transfer-Encoding (the CSV exports), event streams
+// and already compressed workbooks are passed through untouched. Every
+// response gets Vary: Accept-Encoding, as whether it is compressed depends
+// on that header.
+func Gzip(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if c.Request.Method == http.MethodHead || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &gzipWriter{ResponseWriter: original, minSize: minSize}
+		c.Writer = writer
+		defer func() {
+			c.Writer = original
+			_ = writer.finish()
+		}()
+
+		c.Next()
+	}
+}
+
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+	}
+	return false
+}
+
+// gzipWriter buffers the body until it either reaches minSize, at which
+// point it switches to gzip, or the handler finishes or flushes, in which
+// case the buffer is written out uncompressed.
+type gzipWriter struct {
+	gin.ResponseWriter
+	minSize int
+	buf     bytes.Buffer
+	gz      *gzip.Writer
+	decided bool
+}
+
+func (w *gzipWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		if w.passThrough() {
+			if err := w.decide(false); err != nil {
+				return 0, err
+			}
+		} else {
+			w.buf.Write(p)
+			if w.buf.Len() < w.minSize {
+				return len(p), nil
+			}
+			if err := w.decide(true); err != nil {
+				return 0, err
+			}
+			return len(p), nil
+		}
+	}
+	if w.gz != nil {
+		return w.gz.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends whatever is buffered. A handler that flushes before minSize
+// bytes is streaming, so its response stays uncompressed.
+func (w *gzipWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *gzipWriter) passThrough() bool {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" || header.Get("Transfer-Encoding") != "" {
+		return true
+	}
+	contentType := header.Get("Content-Type")
+	return strings.HasPrefix(contentType, "text/event-stream") ||
+		strings.HasPrefix(contentType, "application/vnd.openxmlformats")
+}
+
+// decide commits to compressing or not and writes out the buffer.
+func (w *gzipWriter) decide(compress bool) error {
+	w.decided = true
+	if compress {
+		header := w.Header()
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		if etag := header.Get("ETag"); etag != "" {
+			header.Set("ETag", gzipETag(etag))
+		}
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+		_, err := w.gz.Write(w.buf.Bytes())
+		w.buf.Reset()
+		return err
+	}
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// gzipETag appends GzipETagSuffix to a strong ETag. Weak tags already
+// allow other encodings of the same content and are left alone.
+func gzipETag(etag string) string {
+	if len(etag) < 2 || !strings.HasPrefix(etag, `"`) || !strings.HasSuffix(etag, `"`) {
+		return etag
+	}
+	return etag[:len(etag)-1] + GzipETagSuffix + `"`
+}
+
+func (w *gzipWriter) finish() error {
+	if !w.decided {
+		return w.decide(false)
+	}
+	if w.gz != nil {
+		return w.gz.Close()
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	// maxRequestIDLength keeps client supplied ids from bloating log lines.
+	maxRequestIDLength = 128
+)
+
+// RequestID reads the X-Request-ID header, or generates an id when it is
+// missing, echoes it in the response and stores a logger carrying it on the
+// request context, where zerolog.Ctx finds it.
+func RequestID(log zerolog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := strings.TrimSpace(c.GetHeader(RequestIDHeader))
+		if id == "" || len(id) > maxRequestIDLength {
+			id = uuid.NewString()
+		}
+
+		c.Header(RequestIDHeader, id)
+
+		reqLog := log.With().Str("request_id", id).Logger()
+		c.Request = c.Request.WithContext(reqLog.WithContext(c.Request.Context()))
+		c.Next()
+	}
+}
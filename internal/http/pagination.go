@@ -0,0 +1,96 @@
+package http
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"analytics-service/internal/model"
+)
+
+const (
+	totalCountHeader        = "X-Total-Count"
+	volumeUnitHeader        = "X-Volume-Unit"
+	contractorMatchesHeader = "X-Contractor-Matches"
+)
+
+// setPageHeaders sets X-Total-Count and an RFC 8288 Link header pointing
+// at the first, previous, next and last pages of the current request.
+func setPageHeaders(c *gin.Context, limit, offset int, total int64) {
+	c.Header(totalCountHeader, strconv.FormatInt(total, 10))
+	if links := pageLinks(c.Request.URL, limit, offset, total); links != "" {
+		c.Header("Link", links)
+	}
+}
+
+// setCursorHeaders sets X-Total-Count and, unless this is the last page, a
+// Link header to the next page of a cursor paginated request. Cursors only
+// run forward, so there are no first, prev or last links.
+func setCursorHeaders(c *gin.Context, total int64, nextCursor string) {
+	c.Header(totalCountHeader, strconv.FormatInt(total, 10))
+	if nextCursor == "" {
+		return
+	}
+	target := *c.Request.URL
+	query := target.Query()
+	query.Set("cursor", nextCursor)
+	target.RawQuery = query.Encode()
+	target.Scheme, target.Host = "", ""
+	c.Header("Link", fmt.Sprintf(`<%s>; rel="next"`, target.String()))
+}
+
+// pageLinks builds the Link header value for base, keeping its other query
+// params and replacing limit and offset. prev and next are left out on the
+// first and last page.
+func pageLinks(base *url.URL, limit, offset int, total int64) string {
+	if limit <= 0 {
+		return ""
+	}
+
+	lastOffset := 0
+	if total > 0 {
+		lastOffset = int((total - 1) / int64(limit) * int64(limit))
+	}
+
+	link := func(offset int, rel string) string {
+		target := *base
+		query := target.Query()
+		query.Set("limit", strconv.Itoa(limit))
+		query.Set("offset", strconv.Itoa(offset))
+		target.RawQuery = query.Encode()
+		target.Scheme, target.Host = "", ""
+		return fmt.Sprintf(`<%s>; rel="%s"`, target.String(), rel)
+	}
+
+	links := []string{link(0, "first")}
+	if offset > 0 {
+		links = append(links, link(max(offset-limit, 0), "prev"))
+	}
+	if int64(offset+limit) < total {
+		links = append(links, link(offset+limit, "next"))
+	}
+	links = append(links, link(lastOffset, "last"))
+	return strings.Join(links, ", ")
+}
+
+// setListMeta moves the meta other endpoints put in the body to headers,
+// since paginated lists answer with the bare items array: the volume unit
+// and, for a contractor_name filter, the ids of the matched contractors
+// (names are left out, as they are rarely ASCII).
+func setListMeta(c *gin.Context, matches []model.ContractorMatch, unit model.VolumeUnit) {
+	if unit != "" {
+		c.Header(volumeUnitHeader, string(unit))
+	}
+	if matches == nil {
+		return
+	}
+	ids := make([]string, len(matches))
+	for i, match := range matches {
+		ids[i] = match.ID.String()
+	}
+	// Set directly: c.Header drops an empty value, which here means no match.
+	c.Writer.Header().Set(contractorMatchesHeader, strings.Join(ids, ","))
+}
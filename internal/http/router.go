@@ -6,26 +6,39 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+
+	"analytics-service/internal/http/middleware"
+	"analytics-service/internal/metrics"
 )
 
-func NewRouter(handler *Handler, authMiddleware gin.HandlerFunc, env string) *gin.Engine {
+// NewRouter builds the engine. gzipMinSize is the smallest response body
+// that gets compressed; a negative value disables compression.
+func NewRouter(handler *Handler, authMiddleware gin.HandlerFunc, appMetrics *metrics.Metrics, queryTimeout time.Duration, gzipMinSize int, env string) *gin.Engine {
 	if env == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	router := gin.New()
+	router.Use(middleware.RequestID(handler.log))
 	router.Use(gin.Recovery())
+	router.Use(appMetrics.Middleware())
 	router.Use(cors.New(cors.Config{
 		AllowAllOrigins: true,
 		AllowMethods:    []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:    []string{"*"},
-		ExposeHeaders:   []string{"Content-Type"},
+		ExposeHeaders:   []string{"Content-Type", "ETag", "Link", totalCountHeader, volumeUnitHeader, contractorMatchesHeader, middleware.RequestIDHeader},
 		MaxAge:          12 * time.Hour,
 	}))
+	if gzipMinSize >= 0 {
+		router.Use(middleware.Gzip(gzipMinSize))
+	}
+	router.Use(middleware.QueryTimeout(queryTimeout))
 
 	router.GET("/healthz", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
+	router.GET("/readyz", handler.readyz)
+	router.GET("/metrics", gin.WrapH(appMetrics.Handler()))
 
 	handler.Register(router, authMiddleware)
 
@@ -6,10 +6,19 @@ import (
 	"github.com/rs/zerolog"
 )
 
-func New(env string) zerolog.Logger {
+// New builds the application logger. An empty level logs debug messages in
+// development and from info up elsewhere; level is validated by config.Load.
+func New(env, level string) zerolog.Logger {
 	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
 	if env == "development" {
 		log = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 	}
-	return log
+	logLevel := zerolog.InfoLevel
+	if env == "development" {
+		logLevel = zerolog.DebugLevel
+	}
+	if parsed, err := zerolog.ParseLevel(level); err == nil && level != "" {
+		logLevel = parsed
+	}
+	return log.Level(logLevel)
 }
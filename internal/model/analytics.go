@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,6 +10,8 @@ import (
 type DateRange struct {
 	From time.Time `json:"from"`
 	To   time.Time `json:"to"`
+	// Strict rejects ranges over the maximum instead of clamping them.
+	Strict bool `json:"-"`
 }
 
 type DashboardMetrics struct {
@@ -19,13 +22,46 @@ type DashboardMetrics struct {
 	Contracts    []ContractProgress     `json:"contracts"`
 	Map          MapSummary             `json:"map"`
 	GeneratedFor DateRange              `json:"generated_for"`
+	// Meta records when the dashboard and each of its sections were
+	// computed. Handlers move it to the response meta.
+	Meta *DashboardMeta `json:"meta,omitempty"`
+}
+
+// DashboardMeta holds computation timestamps. A section that was not queried
+// for the scope has no timestamp.
+type DashboardMeta struct {
+	GeneratedAt time.Time           `json:"generated_at"`
+	ComputedAt  DashboardComputedAt `json:"computed_at"`
+}
+
+type DashboardComputedAt struct {
+	Stats       *time.Time `json:"stats,omitempty"`
+	Areas       *time.Time `json:"areas,omitempty"`
+	Contractors *time.Time `json:"contractors,omitempty"`
+	Cameras     *time.Time `json:"cameras,omitempty"`
+	Contracts   *time.Time `json:"contracts,omitempty"`
+	Map         *time.Time `json:"map,omitempty"`
 }
 
 type DashboardStats struct {
-	ActiveTrips       int64 `json:"active_trips"`
-	CompletedTrips    int64 `json:"completed_trips"`
-	TicketsInProgress int64 `json:"tickets_in_progress"`
-	Violations        int64 `json:"violations"`
+	ActiveTrips       int64                   `json:"active_trips"`
+	CompletedTrips    int64                   `json:"completed_trips"`
+	TicketsInProgress int64                   `json:"tickets_in_progress"`
+	Violations        int64                   `json:"violations"`
+	ActiveDrivers     int64                   `json:"active_drivers"`
+	ActiveVehicles    int64                   `json:"active_vehicles"`
+	PreviousPeriod    *DashboardPeriodCompare `json:"previous_period,omitempty"`
+}
+
+// DashboardPeriodCompare holds the range-bound stats of the window of equal
+// length right before the requested one. Change percentages are nil when the
+// previous value is zero.
+type DashboardPeriodCompare struct {
+	Range                   DateRange `json:"range"`
+	CompletedTrips          int64     `json:"completed_trips"`
+	Violations              int64     `json:"violations"`
+	CompletedTripsChangePct *float64  `json:"completed_trips_change_pct"`
+	ViolationsChangePct     *float64  `json:"violations_change_pct"`
 }
 
 type CleaningAreaActivity struct {
@@ -47,6 +83,13 @@ type EntityMetric struct {
 	Count  int64     `json:"count"`
 	Volume float64   `json:"volume"`
 	Share  float64   `json:"share"`
+	// FullName carries the untruncated name when Name was shortened.
+	FullName *string `json:"full_name,omitempty"`
+	// TripCount and ViolationRate are only set on violation leaders ranked
+	// by rate: all trips of the entity and the share of them with a
+	// violation.
+	TripCount     int64    `json:"trip_count,omitempty"`
+	ViolationRate *float64 `json:"violation_rate,omitempty"`
 }
 
 type CameraLoadMetric struct {
@@ -58,8 +101,27 @@ type CameraLoadMetric struct {
 	VolumeEvents int64      `json:"volume_events"`
 	ErrorEvents  int64      `json:"error_events"`
 	ErrorRate    float64    `json:"error_rate"`
+	// Status is OFFLINE without any event in the range, DEGRADED when
+	// ErrorRate exceeds the configured threshold and OK otherwise.
+	Status string `json:"status"`
+}
+
+// OfflineCamera is a camera without LPR or volume events in the range.
+// LastSeenAt is its latest event ever, nil when it never reported one.
+type OfflineCamera struct {
+	CameraID    uuid.UUID  `json:"camera_id"`
+	CameraName  string     `json:"camera_name"`
+	PolygonID   *uuid.UUID `json:"polygon_id,omitempty"`
+	PolygonName *string    `json:"polygon_name,omitempty"`
+	LastSeenAt  *time.Time `json:"last_seen_at"`
 }
 
+const (
+	CameraStatusOK       = "OK"
+	CameraStatusDegraded = "DEGRADED"
+	CameraStatusOffline  = "OFFLINE"
+)
+
 type ContractProgress struct {
 	ContractID     uuid.UUID `json:"contract_id"`
 	Name           string    `json:"name"`
@@ -71,10 +133,67 @@ type ContractProgress struct {
 	MinimalVolume  float64   `json:"minimal_volume_m3"`
 	TotalVolume    float64   `json:"total_volume_m3"`
 	VolumeProgress float64   `json:"volume_progress"`
-	UIStatus       string    `json:"ui_status"`
-	Result         string    `json:"result"`
-	StartAt        time.Time `json:"start_at"`
-	EndAt          time.Time `json:"end_at"`
+	// HasUsageData is false when no contract_usage row exists yet, which
+	// tells "no data yet" apart from a genuine zero cost/volume.
+	HasUsageData bool      `json:"has_usage_data"`
+	UIStatus     string    `json:"ui_status"`
+	Result       string    `json:"result"`
+	StartAt      time.Time `json:"start_at"`
+	EndAt        time.Time `json:"end_at"`
+	// ProjectedEndCost extrapolates TotalCost linearly over the contract
+	// period: nil before the start, the actual cost once it ended.
+	ProjectedEndCost    *float64 `json:"projected_end_cost"`
+	ProjectedOverBudget bool     `json:"projected_over_budget"`
+	// AvgTripsPerDay and AvgVolumePerDay are the pace since the start:
+	// mv_contract_daily totals up to now (or the end) per elapsed day, 0
+	// before the start.
+	AvgTripsPerDay  float64 `json:"avg_trips_per_day"`
+	AvgVolumePerDay float64 `json:"avg_volume_per_day"`
+}
+
+// TripQuality reports likely double-inserted trips: trips entering at most
+// WindowSeconds after an earlier trip of the same driver and vehicle.
+type TripQuality struct {
+	Range          DateRange `json:"range"`
+	WindowSeconds  int64     `json:"window_seconds"`
+	TotalTrips     int64     `json:"total_trips"`
+	DuplicateTrips int64     `json:"duplicate_trips"`
+	DuplicateRate  float64   `json:"duplicate_rate"`
+	// DedupApplied tells whether dashboard counts currently exclude the
+	// duplicates.
+	DedupApplied bool            `json:"dedup_applied"`
+	Duplicates   []DuplicateTrip `json:"duplicates"`
+}
+
+type DuplicateTrip struct {
+	TripID      uuid.UUID `json:"trip_id"`
+	DuplicateOf uuid.UUID `json:"duplicate_of"`
+	DriverID    uuid.UUID `json:"driver_id"`
+	VehicleID   uuid.UUID `json:"vehicle_id"`
+	EntryAt     time.Time `json:"entry_at"`
+}
+
+// ContractProgressBar is the part of ContractProgress that progress bar
+// widgets render.
+type ContractProgressBar struct {
+	ContractID     uuid.UUID `json:"contract_id"`
+	Name           string    `json:"name"`
+	VolumeProgress float64   `json:"volume_progress"`
+	BudgetProgress float64   `json:"budget_progress"`
+}
+
+// ContractSeries is a contract's trips, volume and violations per bucket,
+// read from mv_contract_daily.
+type ContractSeries struct {
+	ContractID uuid.UUID             `json:"contract_id"`
+	Points     []ContractSeriesPoint `json:"points"`
+}
+
+type ContractSeriesPoint struct {
+	Bucket         time.Time `json:"bucket"`
+	TripCount      int64     `json:"trip_count"`
+	TotalVolume    float64   `json:"total_volume_m3"`
+	ViolationCount int64     `json:"violation_count"`
 }
 
 type MapSummary struct {
@@ -105,30 +224,192 @@ type MapCameraState struct {
 	ErrorEvents int64     `json:"error_events"`
 }
 
+// KguComparison holds the trip aggregates of one KGU, i.e. of the tickets
+// it created.
+type KguComparison struct {
+	KguID         uuid.UUID `json:"kgu_id"`
+	KguName       string    `json:"kgu_name"`
+	TripCount     int64     `json:"trip_count"`
+	VolumeM3      float64   `json:"volume_m3"`
+	Violations    int64     `json:"violations"`
+	ViolationRate float64   `json:"violation_rate"`
+	TripShare     float64   `json:"trip_share"`
+}
+
+// MapFeatureCollection is a GeoJSON FeatureCollection of cleaning areas.
+type MapFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []MapAreaFeature `json:"features"`
+}
+
+// MapAreaFeature is a GeoJSON Feature; Geometry is the area polygon as
+// rendered by ST_AsGeoJSON.
+type MapAreaFeature struct {
+	Type       string            `json:"type"`
+	ID         uuid.UUID         `json:"id"`
+	Geometry   json.RawMessage   `json:"geometry"`
+	Properties MapAreaProperties `json:"properties"`
+}
+
+type MapAreaProperties struct {
+	Name        string  `json:"name"`
+	TripCount   int64   `json:"trip_count"`
+	ActiveTrips int64   `json:"active_trips"`
+	Violations  int64   `json:"violations"`
+	Intensity   float64 `json:"intensity"`
+}
+
 type SeriesPoint struct {
 	Bucket time.Time `json:"bucket"`
 	Count  int64     `json:"count"`
 	Value  float64   `json:"value"`
+	// Partial marks a bucket the requested range only partly covers, e.g.
+	// the first and last week of a range that does not start on a Monday.
+	Partial bool `json:"partial,omitempty"`
 }
 
 type TripAnalytics struct {
-	Series         []SeriesPoint     `json:"series"`
-	VolumeSeries   []SeriesPoint     `json:"volume_series"`
-	TopDrivers     []EntityMetric    `json:"top_drivers"`
-	TopContractors []EntityMetric    `json:"top_contractors"`
-	DurationStats  TripDurationStats `json:"duration_stats"`
-	VolumeStats    TripVolumeStats   `json:"volume_stats"`
+	Series        []SeriesPoint            `json:"series"`
+	SeriesSummary SeriesSummary            `json:"series_summary"`
+	EntitySeries  map[string][]SeriesPoint `json:"entity_series,omitempty"`
+	VolumeSeries  []SeriesPoint            `json:"volume_series"`
+	VolumeSummary SeriesSummary            `json:"volume_summary"`
+	// StatusBreakdown counts the trips of the range per status, OK
+	// included; OK carries no severity.
+	StatusBreakdown []ViolationBreakdown `json:"status_breakdown"`
+	TopDrivers      []EntityMetric       `json:"top_drivers"`
+	TopContractors  []EntityMetric       `json:"top_contractors"`
+	DurationStats   TripDurationStats    `json:"duration_stats"`
+	VolumeStats     TripVolumeStats      `json:"volume_stats"`
+}
+
+// SeriesSummary is the headline of a series: Total and TotalValue sum the
+// points' Count and Value, AvgPerBucket averages Count over the returned
+// buckets and PeakBucket is the bucket with the highest Count (the earliest
+// on ties, nil for an empty series).
+type SeriesSummary struct {
+	Total        int64      `json:"total"`
+	TotalValue   float64    `json:"total_value"`
+	AvgPerBucket float64    `json:"avg_per_bucket"`
+	PeakBucket   *time.Time `json:"peak_bucket"`
+	PeakValue    int64      `json:"peak_value"`
+}
+
+type TripStatusSeries struct {
+	Buckets  []time.Time        `json:"buckets"`
+	Statuses []StatusSeriesLine `json:"statuses"`
+}
+
+type StatusSeriesLine struct {
+	Status string  `json:"status"`
+	Counts []int64 `json:"counts"`
+}
+
+// DriverCountSeries holds distinct active drivers per bucket, overall and for
+// the contractors with the most drivers. SeriesPoint.Count is the number of
+// distinct drivers; Value is unused.
+type DriverCountSeries struct {
+	Total       []SeriesPoint            `json:"total"`
+	Contractors []ContractorDriverSeries `json:"contractors"`
+}
+
+type ContractorDriverSeries struct {
+	ContractorID   uuid.UUID     `json:"contractor_id"`
+	ContractorName string        `json:"contractor_name"`
+	Series         []SeriesPoint `json:"series"`
+}
+
+// ContractorDetails is the drill-down of a single contractor: its trips,
+// violations, top drivers and contracts.
+type ContractorDetails struct {
+	ContractorID uuid.UUID            `json:"contractor_id"`
+	Range        DateRange            `json:"range"`
+	TripSeries   []SeriesPoint        `json:"trip_series"`
+	VolumeSeries []SeriesPoint        `json:"volume_series"`
+	Violations   []ViolationBreakdown `json:"violations"`
+	TopDrivers   []EntityMetric       `json:"top_drivers"`
+	Contracts    []ContractProgress   `json:"contracts"`
+}
+
+// ContractorRankSeries is a contractor's position among its peers (the
+// contractors under the same parent organization) per bucket. Peers are
+// only counted, never identified.
+type ContractorRankSeries struct {
+	ContractorID uuid.UUID             `json:"contractor_id"`
+	Metric       string                `json:"metric"`
+	Points       []ContractorRankPoint `json:"points"`
+}
+
+type ContractorRankPoint struct {
+	Bucket time.Time `json:"bucket"`
+	Rank   int64     `json:"rank"`
+	Peers  int64     `json:"peers"`
+	Value  float64   `json:"value"`
+}
+
+// ContractorMatch is a contractor resolved from a contractor_name fragment.
+type ContractorMatch struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type PeakHour struct {
+	Hour      int   `json:"hour"`
+	TripCount int64 `json:"trip_count"`
+}
+
+// TripHeatmap counts trips by local day of week (rows, 0 = Sunday) and hour
+// of day (columns, 0–23).
+type TripHeatmap struct {
+	Matrix       [][]int64 `json:"matrix"`
+	RowTotals    []int64   `json:"row_totals"`
+	ColumnTotals []int64   `json:"column_totals"`
+	Total        int64     `json:"total"`
+}
+
+// FillRateDistribution counts trips by the share of the vehicle body filled
+// at entry (detected entry volume over body volume). Buckets are fixed and
+// always present: 0–25%, 25–50%, 50–75%, 75–100% and over 100%.
+type FillRateDistribution struct {
+	Range   DateRange        `json:"range"`
+	Buckets []FillRateBucket `json:"buckets"`
+	Total   int64            `json:"total"`
+}
+
+// FillRateBucket covers fill rates in [From, To); the 75–100% bucket also
+// includes exactly 100% and the last bucket has no upper bound (To is nil).
+type FillRateBucket struct {
+	Label     string   `json:"label"`
+	From      float64  `json:"from"`
+	To        *float64 `json:"to"`
+	TripCount int64    `json:"trip_count"`
+	Share     float64  `json:"share"`
 }
 
 type TripDurationStats struct {
 	AvgMinutes float64 `json:"avg_minutes"`
+	P50Minutes float64 `json:"p50_minutes"`
+	P90Minutes float64 `json:"p90_minutes"`
 	P95Minutes float64 `json:"p95_minutes"`
+	MaxMinutes float64 `json:"max_minutes"`
 }
 
 type TripVolumeStats struct {
-	AvgVolume float64 `json:"avg_volume"`
-	MaxVolume float64 `json:"max_volume"`
-	MinVolume float64 `json:"min_volume"`
+	AvgVolume     float64 `json:"avg_volume"`
+	MaxVolume     float64 `json:"max_volume"`
+	MinVolume     float64 `json:"min_volume"`
+	AvgExitVolume float64 `json:"avg_exit_volume"`
+	MaxExitVolume float64 `json:"max_exit_volume"`
+	MinExitVolume float64 `json:"min_exit_volume"`
+	// Totals and reading counts let entry and exit be reconciled in
+	// aggregate: trips without an exit reading lower TotalExitVolume.
+	TotalVolume     float64 `json:"total_volume"`
+	TotalExitVolume float64 `json:"total_exit_volume"`
+	EntryReadings   int64   `json:"entry_readings"`
+	ExitReadings    int64   `json:"exit_readings"`
+	// Net volume (entry minus exit) only covers trips with both readings.
+	AvgNetVolume   float64 `json:"avg_net_volume"`
+	TotalNetVolume float64 `json:"total_net_volume"`
 }
 
 type TripDetails struct {
@@ -166,6 +447,34 @@ type TripEvent struct {
 	Captured time.Time `json:"captured_at"`
 }
 
+// Trip timeline entry types.
+const (
+	TimelineTripEntry   = "TRIP_ENTRY"
+	TimelineEntryLPR    = "ENTRY_LPR"
+	TimelineEntryVolume = "ENTRY_VOLUME"
+	TimelineExitLPR     = "EXIT_LPR"
+	TimelineExitVolume  = "EXIT_VOLUME"
+	TimelineTripExit    = "TRIP_EXIT"
+	TimelineViolation   = "VIOLATION"
+)
+
+// TripTimelineEntry is one point of a trip's timeline. Event fields are set
+// for camera events, Violation for violation records.
+type TripTimelineEntry struct {
+	Type      string           `json:"type"`
+	At        time.Time        `json:"at"`
+	EventID   *uuid.UUID       `json:"event_id,omitempty"`
+	CameraID  *uuid.UUID       `json:"camera_id,omitempty"`
+	PhotoURL  *string          `json:"photo_url,omitempty"`
+	Violation *ViolationRecord `json:"violation,omitempty"`
+}
+
+type TripTimeline struct {
+	TripID  uuid.UUID           `json:"trip_id"`
+	Status  string              `json:"status"`
+	Entries []TripTimelineEntry `json:"entries"`
+}
+
 type ViolationRecord struct {
 	Type   string    `json:"type"`
 	Source string    `json:"source"`
@@ -176,15 +485,23 @@ type ViolationRecord struct {
 type ViolationAnalytics struct {
 	Series         []SeriesPoint        `json:"series"`
 	Breakdown      []ViolationBreakdown `json:"breakdown"`
+	Severities     []SeverityBreakdown  `json:"severities"`
 	TopContractors []EntityMetric       `json:"top_contractors"`
 	TopDrivers     []EntityMetric       `json:"top_drivers"`
 	TopCameras     []CameraLoadMetric   `json:"top_cameras"`
 }
 
 type ViolationBreakdown struct {
-	Type  string  `json:"type"`
-	Count int64   `json:"count"`
-	Share float64 `json:"share"`
+	Type     string  `json:"type"`
+	Severity string  `json:"severity"`
+	Count    int64   `json:"count"`
+	Share    float64 `json:"share"`
+}
+
+type SeverityBreakdown struct {
+	Severity string  `json:"severity"`
+	Count    int64   `json:"count"`
+	Share    float64 `json:"share"`
 }
 
 type PerformanceAnalytics struct {
@@ -194,31 +511,41 @@ type PerformanceAnalytics struct {
 }
 
 type ContractorPerformance struct {
-	ContractorID   uuid.UUID `json:"contractor_id"`
-	ContractorName string    `json:"contractor_name"`
-	TripCount      int64     `json:"trip_count"`
-	AvgVolume      float64   `json:"avg_volume"`
-	ViolationRate  float64   `json:"violation_rate"`
-	ActiveDrivers  int64     `json:"active_drivers"`
-	Utilization    float64   `json:"utilization"`
+	ContractorID       uuid.UUID `json:"contractor_id"`
+	ContractorName     string    `json:"contractor_name"`
+	ContractorFullName *string   `json:"contractor_full_name,omitempty"`
+	TripCount          int64     `json:"trip_count"`
+	AvgVolume          float64   `json:"avg_volume"`
+	TotalVolume        float64   `json:"total_volume_m3"`
+	VolumePerTrip      float64   `json:"volume_per_trip"`
+	ViolationCount     int64     `json:"violation_count"`
+	ViolationRate      float64   `json:"violation_rate"`
+	ActiveDrivers      int64     `json:"active_drivers"`
+	Utilization        float64   `json:"utilization"`
 }
 
 type DriverPerformance struct {
-	DriverID      uuid.UUID `json:"driver_id"`
-	DriverName    string    `json:"driver_name"`
-	TripCount     int64     `json:"trip_count"`
-	AvgVolume     float64   `json:"avg_volume"`
-	ViolationRate float64   `json:"violation_rate"`
-	AvgDuration   float64   `json:"avg_duration_minutes"`
+	DriverID       uuid.UUID `json:"driver_id"`
+	DriverName     string    `json:"driver_name"`
+	DriverFullName *string   `json:"driver_full_name,omitempty"`
+	TripCount      int64     `json:"trip_count"`
+	AvgVolume      float64   `json:"avg_volume"`
+	ViolationCount int64     `json:"violation_count"`
+	ViolationRate  float64   `json:"violation_rate"`
+	AvgDuration    float64   `json:"avg_duration_minutes"`
 }
 
 type VehiclePerformance struct {
-	VehicleID     uuid.UUID `json:"vehicle_id"`
-	PlateNumber   string    `json:"plate_number"`
-	TripCount     int64     `json:"trip_count"`
-	AvgFillRate   float64   `json:"avg_fill_rate"`
-	ViolationRate float64   `json:"violation_rate"`
-	IdleHours     float64   `json:"idle_hours"`
+	VehicleID      uuid.UUID `json:"vehicle_id"`
+	PlateNumber    string    `json:"plate_number"`
+	TripCount      int64     `json:"trip_count"`
+	AvgFillRate    float64   `json:"avg_fill_rate"`
+	ViolationCount int64     `json:"violation_count"`
+	ViolationRate  float64   `json:"violation_rate"`
+	IdleHours      float64   `json:"idle_hours"`
+	// FillRateUnavailable is true when the vehicle has no body volume
+	// configured, so AvgFillRate is 0 for lack of data, not an empty truck.
+	FillRateUnavailable bool `json:"fill_rate_unavailable"`
 }
 
 type ContractAnalytics struct {
@@ -239,32 +566,84 @@ type CleaningAreaAnalytics struct {
 	ActiveVehicles   int64      `json:"active_vehicles"`
 	AvgIntervalHours float64    `json:"avg_interval_hours"`
 	IdleHours        float64    `json:"idle_hours"`
+	Neglected        bool       `json:"neglected"`
 	LastTripAt       *time.Time `json:"last_trip_at,omitempty"`
 	GeometryGeoJSON  *string    `json:"geometry_geojson,omitempty"`
 }
 
 type DriverKPI struct {
-	DriverID       uuid.UUID  `json:"driver_id"`
-	DriverName     string     `json:"driver_name"`
+	DriverID           uuid.UUID  `json:"driver_id"`
+	DriverName         string     `json:"driver_name"`
+	DriverFullName     *string    `json:"driver_full_name,omitempty"`
+	ContractorID       *uuid.UUID `json:"contractor_id,omitempty"`
+	ContractorName     *string    `json:"contractor_name,omitempty"`
+	ContractorFullName *string    `json:"contractor_full_name,omitempty"`
+	TripCount          int64      `json:"trip_count"`
+	AvgVolume          float64    `json:"avg_volume"`
+	ViolationCount     int64      `json:"violation_count"`
+	ViolationRate      float64    `json:"violation_rate"`
+	AvgDuration        float64    `json:"avg_duration_minutes"`
+	LastTripAt         *time.Time `json:"last_trip_at,omitempty"`
+}
+
+type TripListItem struct {
+	TripID         uuid.UUID  `json:"trip_id"`
+	Status         string     `json:"status"`
+	EntryAt        time.Time  `json:"entry_at"`
+	ExitAt         *time.Time `json:"exit_at,omitempty"`
+	DriverID       *uuid.UUID `json:"driver_id,omitempty"`
+	DriverName     *string    `json:"driver_name,omitempty"`
 	ContractorID   *uuid.UUID `json:"contractor_id,omitempty"`
 	ContractorName *string    `json:"contractor_name,omitempty"`
-	TripCount      int64      `json:"trip_count"`
-	AvgVolume      float64    `json:"avg_volume"`
-	ViolationRate  float64    `json:"violation_rate"`
-	AvgDuration    float64    `json:"avg_duration_minutes"`
-	LastTripAt     *time.Time `json:"last_trip_at,omitempty"`
+	VolumeEntry    *float64   `json:"detected_volume_entry"`
+	VolumeExit     *float64   `json:"detected_volume_exit"`
+}
+
+type CameraEvent struct {
+	EventID    uuid.UUID `json:"event_id"`
+	Type       string    `json:"type"`
+	DetectedAt time.Time `json:"detected_at"`
+	PhotoURL   *string   `json:"photo_url,omitempty"`
+}
+
+type CameraEventPage struct {
+	Items  []CameraEvent `json:"items"`
+	Total  int64         `json:"total"`
+	Limit  int           `json:"limit"`
+	Offset int           `json:"offset"`
+}
+
+type TripListPage struct {
+	Items  []TripListItem `json:"items"`
+	Total  int64          `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+	// NextCursor continues the list after the last item; it is empty on
+	// the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type DriverKPIPage struct {
+	Items  []DriverKPI `json:"items"`
+	Total  int64       `json:"total"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
 }
 
 type VehicleKPI struct {
-	VehicleID      uuid.UUID  `json:"vehicle_id"`
-	PlateNumber    string     `json:"plate_number"`
-	ContractorID   *uuid.UUID `json:"contractor_id,omitempty"`
-	ContractorName *string    `json:"contractor_name,omitempty"`
-	TripCount      int64      `json:"trip_count"`
-	AvgFillRate    float64    `json:"avg_fill_rate"`
-	ViolationRate  float64    `json:"violation_rate"`
-	IdleHours      float64    `json:"idle_hours"`
-	LastTripAt     *time.Time `json:"last_trip_at,omitempty"`
+	VehicleID          uuid.UUID  `json:"vehicle_id"`
+	PlateNumber        string     `json:"plate_number"`
+	ContractorID       *uuid.UUID `json:"contractor_id,omitempty"`
+	ContractorName     *string    `json:"contractor_name,omitempty"`
+	ContractorFullName *string    `json:"contractor_full_name,omitempty"`
+	TripCount          int64      `json:"trip_count"`
+	AvgFillRate        float64    `json:"avg_fill_rate"`
+	ViolationCount     int64      `json:"violation_count"`
+	ViolationRate      float64    `json:"violation_rate"`
+	IdleHours          float64    `json:"idle_hours"`
+	LastTripAt         *time.Time `json:"last_trip_at,omitempty"`
+	// FillRateUnavailable: see VehiclePerformance.
+	FillRateUnavailable bool `json:"fill_rate_unavailable"`
 }
 
 type PolygonLoadMetric struct {
@@ -275,6 +654,15 @@ type PolygonLoadMetric struct {
 	ErrorEvents int64     `json:"error_events"`
 }
 
+// PolygonCoverage counts the cameras installed on a polygon; Uncovered marks
+// polygons without any camera.
+type PolygonCoverage struct {
+	PolygonID   uuid.UUID `json:"polygon_id"`
+	PolygonName string    `json:"polygon_name"`
+	CameraCount int64     `json:"camera_count"`
+	Uncovered   bool      `json:"uncovered"`
+}
+
 type TechnicalAnalytics struct {
 	Cameras        []CameraLoadMetric  `json:"cameras"`
 	Polygons       []PolygonLoadMetric `json:"polygons"`
@@ -283,3 +671,38 @@ type TechnicalAnalytics struct {
 	TotalEvents    int64               `json:"total_events"`
 	EventFrequency float64             `json:"event_frequency_per_hour"`
 }
+
+type ViewRefreshResult struct {
+	View       string  `json:"view"`
+	Status     string  `json:"status"`
+	DurationMs int64   `json:"duration_ms"`
+	Error      *string `json:"error,omitempty"`
+}
+
+// Readiness is the /readyz payload. Status is READY, DEGRADED (optional
+// checks failed, still serving) or UNAVAILABLE.
+type Readiness struct {
+	Status   string            `json:"status"`
+	Checks   map[string]string `json:"checks"`
+	Failed   string            `json:"failed,omitempty"`
+	Warnings []string          `json:"warnings,omitempty"`
+}
+
+// ViewConsistency compares a materialized view with the same aggregate
+// computed live from trips. Status is OK, DRIFT or SKIPPED (view missing).
+// Volume fields are omitted for views without a volume column.
+type ViewConsistency struct {
+	View        string   `json:"view"`
+	Status      string   `json:"status"`
+	MVTrips     int64    `json:"mv_trips"`
+	LiveTrips   int64    `json:"live_trips"`
+	TripDelta   int64    `json:"trip_delta"`
+	MVVolume    *float64 `json:"mv_volume_m3,omitempty"`
+	LiveVolume  *float64 `json:"live_volume_m3,omitempty"`
+	VolumeDelta *float64 `json:"volume_delta_m3,omitempty"`
+}
+
+type ConsistencyReport struct {
+	Since time.Time         `json:"since"`
+	Views []ViewConsistency `json:"views"`
+}
@@ -1,6 +1,9 @@
 package model
 
 import (
+	"encoding/base64"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,18 +12,238 @@ import (
 type GroupBy string
 
 const (
+	GroupByHour  GroupBy = "hour"
 	GroupByDay   GroupBy = "day"
 	GroupByWeek  GroupBy = "week"
 	GroupByMonth GroupBy = "month"
 )
 
+type GroupByEntity string
+
+const (
+	GroupByEntityContractor GroupByEntity = "contractor"
+	GroupByEntityDriver     GroupByEntity = "driver"
+	GroupByEntityArea       GroupByEntity = "area"
+)
+
+// VolumeUnit is the unit volumes are reported in. They are stored in m³ and
+// converted to tonnes with the configured density.
+type VolumeUnit string
+
+const (
+	VolumeUnitM3     VolumeUnit = "m3"
+	VolumeUnitTonnes VolumeUnit = "tonnes"
+)
+
+// Trip statuses as stored in trips.status; everything but OK is a violation.
+const (
+	TripStatusOK            = "OK"
+	TripStatusNoLPREvent    = "NO_LPR_EVENT"
+	TripStatusNoVolumeEvent = "NO_VOLUME_EVENT"
+	TripStatusCameraError   = "CAMERA_ERROR"
+	TripStatusMismatchPlate = "MISMATCH_PLATE"
+)
+
+func IsTripStatus(status string) bool {
+	switch status {
+	case TripStatusOK, TripStatusNoLPREvent, TripStatusNoVolumeEvent, TripStatusCameraError, TripStatusMismatchPlate:
+		return true
+	default:
+		return false
+	}
+}
+
+// ViolationStatuses lists the trip statuses that count as violations.
+func ViolationStatuses() []string {
+	return []string{TripStatusNoLPREvent, TripStatusNoVolumeEvent, TripStatusCameraError, TripStatusMismatchPlate}
+}
+
+// Violation severities. Severity is not stored; statuses map to it through
+// configuration.
+const (
+	SeverityHigh    = "HIGH"
+	SeverityMedium  = "MEDIUM"
+	SeverityLow     = "LOW"
+	SeverityUnknown = "UNKNOWN"
+)
+
+func IsSeverity(severity string) bool {
+	switch severity {
+	case SeverityHigh, SeverityMedium, SeverityLow:
+		return true
+	default:
+		return false
+	}
+}
+
+// Contract statuses and results as derived from the contract period and
+// usage; see ContractProgress.
+const (
+	ContractStatusPlanned = "PLANNED"
+	ContractStatusActive  = "ACTIVE"
+	ContractStatusExpired = "EXPIRED"
+
+	ContractResultSuccess    = "SUCCESS"
+	ContractResultFail       = "FAIL"
+	ContractResultOverBudget = "OVER_BUDGET"
+	ContractResultOnTrack    = "ON_TRACK"
+	ContractResultBehind     = "BEHIND"
+	ContractResultNone       = "NONE"
+)
+
+func IsContractStatus(status string) bool {
+	switch status {
+	case ContractStatusPlanned, ContractStatusActive, ContractStatusExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+func IsContractResult(result string) bool {
+	switch result {
+	case ContractResultSuccess, ContractResultFail, ContractResultOverBudget, ContractResultOnTrack, ContractResultBehind, ContractResultNone:
+		return true
+	default:
+		return false
+	}
+}
+
+// ContractFilter narrows the contract report; empty fields match any
+// contract.
+type ContractFilter struct {
+	Status string
+	Result string
+}
+
 type AnalyticsFilter struct {
 	Range        DateRange
 	ContractorID *uuid.UUID
-	DriverID     *uuid.UUID
-	PolygonID    *uuid.UUID
-	CameraID     *uuid.UUID
-	GroupBy      GroupBy
+	// ContractorIDs, when non-nil, restricts results to these contractors;
+	// an empty slice matches nothing. It holds repeated contractor_id params
+	// and the contractors a ContractorName resolved to.
+	ContractorIDs []uuid.UUID
+	// ContractorName is a name fragment resolved to ContractorIDs by the
+	// service, within any ContractorIDs already given.
+	ContractorName string
+	DriverID       *uuid.UUID
+	VehicleID      *uuid.UUID
+	PolygonID      *uuid.UUID
+	CameraID       *uuid.UUID
+	// CreatedByOrgID narrows city scope to the tickets created by this
+	// organization (a KGU); other scopes may not set it.
+	CreatedByOrgID *uuid.UUID
+	GroupBy        GroupBy
+	GroupByEntity  GroupByEntity
+	// Interval, when set, replaces GroupBy with fixed-width buckets computed
+	// from the trips table (date_bin).
+	Interval time.Duration
+	// TZ is the zone buckets are cut in; nil means UTC.
+	TZ *time.Location
+	// Statuses restricts trip lists and violation analytics to the given
+	// trip statuses.
+	Statuses []string
+	// Severities restricts violation analytics to statuses mapped to one of
+	// the given severities.
+	Severities []string
+	// Unit is the unit volumes are returned in; it does not affect the
+	// queries. Empty means VolumeUnitM3.
+	Unit VolumeUnit
+}
+
+// SortOrder orders a ranked list by Key; an empty Key keeps the list's
+// default order.
+type SortOrder struct {
+	Key  string
+	Desc bool
+}
+
+// BBox is a map viewport in WGS 84 longitude/latitude degrees.
+type BBox struct {
+	MinLon float64 `json:"min_lon"`
+	MinLat float64 `json:"min_lat"`
+	MaxLon float64 `json:"max_lon"`
+	MaxLat float64 `json:"max_lat"`
+}
+
+// Violation leader orderings.
+const (
+	LeaderOrderCount = "count"
+	LeaderOrderShare = "share"
+)
+
+// Violation leader rankings: by violation count or by violations per trip.
+const (
+	LeaderByCount = "count"
+	LeaderByRate  = "rate"
+)
+
+// Trip leader rankings: by trip count or by hauled volume.
+const (
+	RankByCount  = "count"
+	RankByVolume = "volume"
+)
+
+// LeaderOptions shapes a violation leader list. Only entities with at least
+// MinCount violations and at least MinShare of all violations in the range
+// qualify; OrderBy is LeaderOrderCount or LeaderOrderShare. By is
+// LeaderByCount or LeaderByRate; ranking by rate also requires MinTrips
+// trips and takes precedence over OrderBy.
+type LeaderOptions struct {
+	Limit    int
+	MinCount int64
+	MinShare float64
+	OrderBy  string
+	By       string
+	MinTrips int64
+}
+
+type Pagination struct {
+	Limit  int
+	Offset int
+	// LastN, when set, asks for the LastN most recent items regardless of
+	// the date range instead of a page.
+	LastN int
+	// After, when set, continues a trip list after this trip instead of
+	// skipping Offset rows.
+	After *TripCursor
+}
+
+// TripCursor is the keyset position of a trip in the trip list, which is
+// ordered by (entry_at, id) descending.
+type TripCursor struct {
+	EntryAt time.Time
+	ID      uuid.UUID
+}
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// Encode returns the cursor as an opaque, URL safe token.
+func (c TripCursor) Encode() string {
+	raw := c.EntryAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseTripCursor decodes a token returned by Encode. Any token that was
+// not produced by Encode, truncated or edited ones included, is rejected.
+func ParseTripCursor(token string) (TripCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return TripCursor{}, errInvalidCursor
+	}
+	entryStr, idStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return TripCursor{}, errInvalidCursor
+	}
+	entryAt, err := time.Parse(time.RFC3339Nano, entryStr)
+	if err != nil {
+		return TripCursor{}, errInvalidCursor
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil || id == uuid.Nil {
+		return TripCursor{}, errInvalidCursor
+	}
+	return TripCursor{EntryAt: entryAt, ID: id}, nil
 }
 
 func (f AnalyticsFilter) ClampRange(defaultRange, maxRange int) AnalyticsFilter {
@@ -37,11 +260,31 @@ func (f AnalyticsFilter) ClampRange(defaultRange, maxRange int) AnalyticsFilter
 	return f
 }
 
+func (f AnalyticsFilter) Location() *time.Location {
+	if f.TZ == nil {
+		return time.UTC
+	}
+	return f.TZ
+}
+
 func (f AnalyticsFilter) Bucket() GroupBy {
 	switch f.GroupBy {
-	case GroupByWeek, GroupByMonth:
+	case GroupByHour, GroupByWeek, GroupByMonth:
 		return f.GroupBy
 	default:
 		return GroupByDay
 	}
 }
+
+// SubDaily reports whether buckets are finer than the daily materialized
+// views, so series must be computed from the trips table.
+func (f AnalyticsFilter) SubDaily() bool {
+	return f.Interval > 0 || f.GroupBy == GroupByHour
+}
+
+// FromTrips reports whether series must be computed from the trips table:
+// buckets are finer than the daily views or a camera, which the views do
+// not keep, is chosen.
+func (f AnalyticsFilter) FromTrips() bool {
+	return f.SubDaily() || f.CameraID != nil
+}
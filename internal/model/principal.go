@@ -1,6 +1,10 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
 
 type UserRole string
 
@@ -17,12 +21,27 @@ const (
 )
 
 type Principal struct {
-	UserID   uuid.UUID
-	OrgID    uuid.UUID
+	UserID uuid.UUID
+	OrgID  uuid.UUID
+	// OrgIDs lists further organizations the user belongs to, e.g. a KGU
+	// employee overseeing several districts.
+	OrgIDs   []uuid.UUID
 	Role     UserRole
 	DriverID *uuid.UUID
 }
 
+// Organizations returns OrgID followed by the distinct OrgIDs.
+func (p Principal) Organizations() []uuid.UUID {
+	orgs := []uuid.UUID{p.OrgID}
+	for _, id := range p.OrgIDs {
+		if id == uuid.Nil || slices.Contains(orgs, id) {
+			continue
+		}
+		orgs = append(orgs, id)
+	}
+	return orgs
+}
+
 func (p Principal) IsAkimat() bool {
 	return p.Role == UserRoleAkimatAdmin || p.Role == UserRoleAkimatUser
 }
@@ -48,3 +67,12 @@ func (p Principal) IsContractor() bool {
 func (p Principal) IsDriver() bool {
 	return p.Role == UserRoleDriver
 }
+
+// HasDriverIdentity reports whether a driver principal carries the DriverID
+// its data is scoped by. Non-driver principals always pass.
+func (p Principal) HasDriverIdentity() bool {
+	if !p.IsDriver() {
+		return true
+	}
+	return p.DriverID != nil && *p.DriverID != uuid.Nil
+}
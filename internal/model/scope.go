@@ -1,6 +1,10 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
 
 type ScopeType string
 
@@ -9,15 +13,34 @@ const (
 	ScopeKgu        ScopeType = "KGU"
 	ScopeContractor ScopeType = "CONTRACTOR"
 	ScopeTechnical  ScopeType = "TECHNICAL"
+	// ScopeDriver limits a driver to their own trips. It is derived from the
+	// driver_id claim and cannot be assigned through role mappings.
+	ScopeDriver ScopeType = "DRIVER"
 )
 
 type Scope struct {
-	Type               ScopeType
-	OrgID              *uuid.UUID
+	Type  ScopeType
+	OrgID *uuid.UUID
+	// OrganizationIDs lists every organization a KGU or contractor scope
+	// acts for, OrgID first; users may belong to several.
 	OrganizationIDs    []uuid.UUID
 	ContractorIDs      []uuid.UUID
 	IncludeContractors bool
 	TechnicalOnly      bool
+	// DriverID is set for ScopeDriver.
+	DriverID *uuid.UUID
+}
+
+// Organizations returns OrganizationIDs, falling back to OrgID for scopes
+// built with a single organization.
+func (s Scope) Organizations() []uuid.UUID {
+	if len(s.OrganizationIDs) > 0 {
+		return s.OrganizationIDs
+	}
+	if s.OrgID != nil {
+		return []uuid.UUID{*s.OrgID}
+	}
+	return nil
 }
 
 func (s Scope) AllowsCity() bool {
@@ -32,8 +55,8 @@ func (s Scope) AllowsContractor(contractorID uuid.UUID) bool {
 	if s.Type == ScopeCity {
 		return true
 	}
-	if s.Type == ScopeContractor && s.OrgID != nil {
-		return *s.OrgID == contractorID
+	if s.Type == ScopeContractor {
+		return slices.Contains(s.Organizations(), contractorID)
 	}
 	for _, id := range s.ContractorIDs {
 		if id == contractorID {
@@ -42,3 +65,32 @@ func (s Scope) AllowsContractor(contractorID uuid.UUID) bool {
 	}
 	return false
 }
+
+// Section is an analytics area a client may show in its menu.
+type Section string
+
+const (
+	SectionDashboard   Section = "dashboard"
+	SectionTrips       Section = "trips"
+	SectionTripList    Section = "trip_list"
+	SectionViolations  Section = "violations"
+	SectionPerformance Section = "performance"
+	SectionContracts   Section = "contracts"
+	SectionAreas       Section = "areas"
+	SectionDrivers     Section = "drivers"
+	SectionVehicles    Section = "vehicles"
+	SectionTechnical   Section = "technical"
+)
+
+// Sections lists every Section in menu order.
+var Sections = []Section{
+	SectionDashboard, SectionTrips, SectionTripList, SectionViolations, SectionPerformance,
+	SectionContracts, SectionAreas, SectionDrivers, SectionVehicles, SectionTechnical,
+}
+
+// Capabilities tells which sections the caller may open. Scope is empty for
+// roles without analytics access.
+type Capabilities struct {
+	Scope    ScopeType        `json:"scope"`
+	Sections map[Section]bool `json:"sections"`
+}
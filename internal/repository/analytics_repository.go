@@ -3,26 +3,58 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rs/zerolog"
 	"gorm.io/gorm"
 
 	"analytics-service/internal/model"
 )
 
+var materializedViews = []string{
+	"mv_trip_daily",
+	"mv_violation_daily",
+	"mv_contract_daily",
+	"mv_cleaning_area_daily",
+}
+
+var ErrViewMissing = errors.New("materialized view does not exist")
+
+// AnalyticsOptions tunes the analytics queries.
+type AnalyticsOptions struct {
+	// NameCacheTTL keeps driver and contractor names in memory for this long
+	// so the trip leaderboards skip their name joins; zero disables it.
+	NameCacheTTL time.Duration
+	// CameraDegradedErrorRate is the error rate above which a camera is
+	// reported as DEGRADED.
+	CameraDegradedErrorRate float64
+}
+
 type AnalyticsRepository struct {
-	db *gorm.DB
+	db                  *gorm.DB
+	log                 zerolog.Logger
+	names               *nameCache
+	cameraDegradedAbove float64
 }
 
-func NewAnalyticsRepository(db *gorm.DB) *AnalyticsRepository {
-	return &AnalyticsRepository{db: db}
+func NewAnalyticsRepository(db *gorm.DB, log zerolog.Logger, opts AnalyticsOptions) *AnalyticsRepository {
+	repo := &AnalyticsRepository{db: db, log: log, cameraDegradedAbove: opts.CameraDegradedErrorRate}
+	if opts.NameCacheTTL > 0 {
+		repo.names = newNameCache(opts.NameCacheTTL)
+	}
+	return repo
 }
 
-func (r *AnalyticsRepository) DashboardStats(ctx context.Context, scope model.Scope, rng model.DateRange) (model.DashboardStats, error) {
+// DashboardStats counts trips, violations, tickets and the drivers and
+// vehicles with at least one trip in rng. A positive dedupWindow
+// leaves out likely duplicate trips (see duplicateTripMatch).
+func (r *AnalyticsRepository) DashboardStats(ctx context.Context, scope model.Scope, rng model.DateRange, dedupWindow time.Duration) (model.DashboardStats, error) {
 	if !r.tablesAvailable(ctx, "trips", "tickets") {
 		return model.DashboardStats{}, nil
 	}
@@ -37,11 +69,17 @@ func (r *AnalyticsRepository) DashboardStats(ctx context.Context, scope model.Sc
 		Select(`
 			SUM(CASE WHEN tr.exit_at IS NULL THEN 1 ELSE 0 END) AS active_trips,
 			SUM(CASE WHEN tr.exit_at IS NOT NULL AND tr.entry_at BETWEEN ? AND ? THEN 1 ELSE 0 END) AS completed_trips,
-			SUM(CASE WHEN tr.status <> 'OK' AND tr.entry_at BETWEEN ? AND ? THEN 1 ELSE 0 END) AS violations`,
+			SUM(CASE WHEN tr.status <> 'OK' AND tr.entry_at BETWEEN ? AND ? THEN 1 ELSE 0 END) AS violations,
+			COUNT(DISTINCT CASE WHEN tr.entry_at BETWEEN ? AND ? THEN tr.driver_id END) AS active_drivers,
+			COUNT(DISTINCT CASE WHEN tr.entry_at BETWEEN ? AND ? THEN tr.vehicle_id END) AS active_vehicles`,
+			timeRangeFrom, timeRangeTo, timeRangeFrom, timeRangeTo,
 			timeRangeFrom, timeRangeTo, timeRangeFrom, timeRangeTo).
 		Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id")
 
 	query = applyTripScope(query, scope)
+	if dedupWindow > 0 {
+		query = query.Where(fmt.Sprintf("NOT EXISTS (SELECT 1 FROM trips dup WHERE %s)", duplicateTripMatch), dedupWindow.Seconds())
+	}
 
 	if err := query.Scan(&stats).Error; err != nil {
 		return model.DashboardStats{}, err
@@ -60,7 +98,11 @@ func (r *AnalyticsRepository) DashboardStats(ctx context.Context, scope model.Sc
 	return stats, nil
 }
 
-func (r *AnalyticsRepository) CleaningAreaActivity(ctx context.Context, scope model.Scope, rng model.DateRange) ([]model.CleaningAreaActivity, error) {
+// CleaningAreaActivity aggregates trips per cleaning area. Open trips count as
+// active only when they entered at or after activeSince; pass the zero time to
+// treat every open trip as active. A non-nil bbox keeps only areas whose
+// geometry intersects it.
+func (r *AnalyticsRepository) CleaningAreaActivity(ctx context.Context, scope model.Scope, rng model.DateRange, activeSince time.Time, bbox *model.BBox) ([]model.CleaningAreaActivity, error) {
 	if !r.tablesAvailable(ctx, "trips", "tickets") {
 		return nil, nil
 	}
@@ -73,16 +115,27 @@ func (r *AnalyticsRepository) CleaningAreaActivity(ctx context.Context, scope mo
 	}
 	var rows []row
 
+	activeExpr := "SUM(CASE WHEN tr.exit_at IS NULL THEN 1 ELSE 0 END) AS active_trips"
+	var activeArgs []interface{}
+	if !activeSince.IsZero() {
+		activeExpr = "SUM(CASE WHEN tr.exit_at IS NULL AND tr.entry_at >= ? THEN 1 ELSE 0 END) AS active_trips"
+		activeArgs = append(activeArgs, activeSince)
+	}
+
 	query := r.db.WithContext(ctx).
 		Table("trips tr").
 		Select(`t.cleaning_area_id AS cleaning_area_id,
 			COUNT(*) AS trips,
-			SUM(CASE WHEN tr.exit_at IS NULL THEN 1 ELSE 0 END) AS active_trips,
-			MAX(CASE WHEN tr.status <> 'OK' THEN 1 ELSE 0 END) AS has_violations`).
+			`+activeExpr+`,
+			MAX(CASE WHEN tr.status <> 'OK' THEN 1 ELSE 0 END) AS has_violations`, activeArgs...).
 		Joins("JOIN tickets t ON t.id = tr.ticket_id").
 		Where("tr.entry_at BETWEEN ? AND ?", rng.From, rng.To).
 		Group("t.cleaning_area_id")
 
+	if bbox != nil && r.spatialAvailable(ctx, "cleaning_areas") {
+		query = query.Joins("JOIN cleaning_areas ca ON ca.id = t.cleaning_area_id")
+		query = applyBBox(query, "ca.geometry", bbox)
+	}
 	query = applyTripScope(query, scope)
 
 	if err := query.Scan(&rows).Error; err != nil {
@@ -134,6 +187,15 @@ func (r *AnalyticsRepository) CleaningAreaAnalytics(ctx context.Context, scope m
 	}
 	var rows []row
 
+	// Without PostGIS the geometry column cannot be rendered; areas are still
+	// reported, with a null geometry.
+	geometry := "NULL::text AS geometry"
+	groupBy := "mv.cleaning_area_id, ca.name, ca.description"
+	if r.functionExists(ctx, "st_asgeojson") {
+		geometry = "ST_AsGeoJSON(ca.geometry)::text AS geometry"
+		groupBy += ", ca.geometry"
+	}
+
 	query := r.db.WithContext(ctx).
 		Table("mv_cleaning_area_daily mv").
 		Select(`mv.cleaning_area_id,
@@ -146,10 +208,10 @@ func (r *AnalyticsRepository) CleaningAreaAnalytics(ctx context.Context, scope m
 			SUM(mv.active_vehicles) AS active_vehicles,
 			MIN(mv.first_entry_at) AS first_entry,
 			MAX(mv.last_exit_at) AS last_exit,
-			COALESCE(ST_AsGeoJSON(ca.geometry)::text, NULL) AS geometry`).
+			`+geometry).
 		Joins("LEFT JOIN cleaning_areas ca ON ca.id = mv.cleaning_area_id").
 		Where("mv.bucket BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
-		Group("mv.cleaning_area_id, ca.name, ca.description, ca.geometry")
+		Group(groupBy)
 
 	query = applyMVCleaningAreaScope(query, scope)
 
@@ -255,8 +317,8 @@ func (r *AnalyticsRepository) ContractorActivitySplit(ctx context.Context, scope
 			Select("org.id, org.name").
 			Where("org.type = ? AND org.is_active = ?", orgTypeContractor, true)
 
-		if scope.Type == model.ScopeKgu && scope.OrgID != nil {
-			orgQuery = orgQuery.Where("org.parent_org_id = ?", *scope.OrgID)
+		if orgs := scope.Organizations(); scope.Type == model.ScopeKgu && len(orgs) > 0 {
+			orgQuery = orgQuery.Where("org.parent_org_id IN ?", orgs)
 		}
 
 		if len(ids) > 0 {
@@ -278,7 +340,9 @@ func (r *AnalyticsRepository) ContractorActivitySplit(ctx context.Context, scope
 	return active, idle, nil
 }
 
-func (r *AnalyticsRepository) CameraLoad(ctx context.Context, scope model.Scope, rng model.DateRange) ([]model.CameraLoadMetric, error) {
+// CameraLoad counts the events and error trips of each camera in scope over
+// rng. A non-nil cameraID narrows it to that one camera.
+func (r *AnalyticsRepository) CameraLoad(ctx context.Context, scope model.Scope, rng model.DateRange, cameraID *uuid.UUID) ([]model.CameraLoadMetric, error) {
 	if !r.tablesAvailable(ctx, "cameras", "polygons", "trips", "lpr_events", "volume_events") {
 		return nil, nil
 	}
@@ -310,7 +374,7 @@ func (r *AnalyticsRepository) CameraLoad(ctx context.Context, scope model.Scope,
 		Table("trips").
 		Select("camera_id, COUNT(*) AS cnt").
 		Where("camera_id IS NOT NULL AND status::text IN ? AND entry_at BETWEEN ? AND ?",
-			[]string{"NO_LPR_EVENT", "NO_VOLUME_EVENT", "CAMERA_ERROR", "MISMATCH_PLATE"}, rng.From, rng.To).
+			[]string{model.TripStatusNoLPREvent, model.TripStatusNoVolumeEvent, model.TripStatusCameraError, model.TripStatusMismatchPlate}, rng.From, rng.To).
 		Group("camera_id")
 
 	query := r.db.WithContext(ctx).
@@ -327,16 +391,12 @@ func (r *AnalyticsRepository) CameraLoad(ctx context.Context, scope model.Scope,
 		Joins("LEFT JOIN (?) AS v ON v.camera_id = c.id", subVolume).
 		Joins("LEFT JOIN (?) AS e ON e.camera_id = c.id", subErrors)
 
-	if scope.Type != model.ScopeCity && scope.Type != model.ScopeTechnical {
-		cameraIDs := r.db.WithContext(ctx).
-			Table("trips tr").
-			Select("DISTINCT tr.camera_id").
-			Joins("JOIN tickets t ON t.id = tr.ticket_id").
-			Where("tr.camera_id IS NOT NULL").
-			Where("tr.entry_at BETWEEN ? AND ?", rng.From, rng.To)
-		cameraIDs = applyTripScope(cameraIDs, scope)
+	if cameraIDs := r.scopedCameraIDs(ctx, scope, rng); cameraIDs != nil {
 		query = query.Where("c.id IN (?)", cameraIDs)
 	}
+	if cameraID != nil {
+		query = query.Where("c.id = ?", *cameraID)
+	}
 
 	if err := query.Scan(&rows).Error; err != nil {
 		return nil, err
@@ -347,7 +407,14 @@ func (r *AnalyticsRepository) CameraLoad(ctx context.Context, scope model.Scope,
 		totalEvents := row.LprEvents + row.VolumeEvents
 		errorRate := 0.0
 		if totalEvents > 0 {
-			errorRate = float64(row.ErrorEvents) / float64(totalEvents)
+			errorRate = clamp(float64(row.ErrorEvents) / float64(totalEvents))
+		}
+		status := model.CameraStatusOK
+		switch {
+		case totalEvents == 0:
+			status = model.CameraStatusOffline
+		case errorRate > r.cameraDegradedAbove:
+			status = model.CameraStatusDegraded
 		}
 		result = append(result, model.CameraLoadMetric{
 			CameraID:     row.CameraID,
@@ -357,13 +424,74 @@ func (r *AnalyticsRepository) CameraLoad(ctx context.Context, scope model.Scope,
 			LprEvents:    row.LprEvents,
 			VolumeEvents: row.VolumeEvents,
 			ErrorEvents:  row.ErrorEvents,
-			ErrorRate:    clamp(errorRate),
+			ErrorRate:    errorRate,
+			Status:       status,
 		})
 	}
 
 	return result, nil
 }
 
+// scopedCameraIDs selects the cameras that recorded trips of scope in rng,
+// for use as an IN subquery. It returns nil for the city and technical
+// scopes, which see every camera.
+func (r *AnalyticsRepository) scopedCameraIDs(ctx context.Context, scope model.Scope, rng model.DateRange) *gorm.DB {
+	if scope.Type == model.ScopeCity || scope.Type == model.ScopeTechnical {
+		return nil
+	}
+	cameraIDs := r.db.WithContext(ctx).
+		Table("trips tr").
+		Select("DISTINCT tr.camera_id").
+		Joins("JOIN tickets t ON t.id = tr.ticket_id").
+		Where("tr.camera_id IS NOT NULL").
+		Where("tr.entry_at BETWEEN ? AND ?", rng.From, rng.To)
+	return applyTripScope(cameraIDs, scope)
+}
+
+// OfflineCameras lists cameras without LPR or volume events in rng, the ones
+// dark the longest (or never seen) first.
+func (r *AnalyticsRepository) OfflineCameras(ctx context.Context, rng model.DateRange) ([]model.OfflineCamera, error) {
+	cameras := []model.OfflineCamera{}
+	if !r.tablesAvailable(ctx, "cameras", "polygons", "lpr_events", "volume_events") {
+		return cameras, nil
+	}
+
+	subLpr := r.db.WithContext(ctx).
+		Table("lpr_events").
+		Select("camera_id, COUNT(*) AS cnt").
+		Where("detected_at BETWEEN ? AND ?", rng.From, rng.To).
+		Group("camera_id")
+
+	subVolume := r.db.WithContext(ctx).
+		Table("volume_events").
+		Select("camera_id, COUNT(*) AS cnt").
+		Where("detected_at BETWEEN ? AND ?", rng.From, rng.To).
+		Group("camera_id")
+
+	// last_seen_at ignores the range; GREATEST skips the NULL of a table
+	// the camera never reported to.
+	err := r.db.WithContext(ctx).
+		Table("cameras c").
+		Select(`c.id AS camera_id,
+			COALESCE(c.name, 'Camera') AS camera_name,
+			c.polygon_id AS polygon_id,
+			subp.name AS polygon_name,
+			GREATEST(
+				(SELECT MAX(le.detected_at) FROM lpr_events le WHERE le.camera_id = c.id),
+				(SELECT MAX(ve.detected_at) FROM volume_events ve WHERE ve.camera_id = c.id)
+			) AS last_seen_at`).
+		Joins("LEFT JOIN polygons subp ON subp.id = c.polygon_id").
+		Joins("LEFT JOIN (?) AS l ON l.camera_id = c.id", subLpr).
+		Joins("LEFT JOIN (?) AS v ON v.camera_id = c.id", subVolume).
+		Where("COALESCE(l.cnt, 0) + COALESCE(v.cnt, 0) = 0").
+		Order("last_seen_at ASC NULLS FIRST, camera_name ASC").
+		Scan(&cameras).Error
+	if err != nil {
+		return nil, err
+	}
+	return cameras, nil
+}
+
 func (r *AnalyticsRepository) ContractProgress(ctx context.Context, scope model.Scope) ([]model.ContractProgress, error) {
 	if !r.tablesAvailable(ctx, "contracts", "organizations", "contract_usage") {
 		return nil, nil
@@ -378,6 +506,9 @@ func (r *AnalyticsRepository) ContractProgress(ctx context.Context, scope model.
 		TotalCost      float64
 		MinimalVolume  float64
 		TotalVolume    float64
+		HasUsageData   bool
+		PaceTrips      int64
+		PaceVolume     float64
 		StartAt        time.Time
 		EndAt          time.Time
 		UIStatus       string
@@ -387,6 +518,14 @@ func (r *AnalyticsRepository) ContractProgress(ctx context.Context, scope model.
 
 	now := time.Now()
 
+	// The pace totals cover each contract's own window up to now; without
+	// the view they stay 0.
+	paceColumns := "0 AS pace_trips, 0 AS pace_volume"
+	hasPace := r.relationExists(ctx, "mv_contract_daily")
+	if hasPace {
+		paceColumns = "COALESCE(pace.trips, 0) AS pace_trips, COALESCE(pace.volume, 0) AS pace_volume"
+	}
+
 	query := r.db.WithContext(ctx).
 		Table("contracts c").
 		Select(`c.id AS contract_id,
@@ -397,12 +536,24 @@ func (r *AnalyticsRepository) ContractProgress(ctx context.Context, scope model.
 			COALESCE(u.total_cost, 0) AS total_cost,
 			c.minimal_volume_m3,
 			COALESCE(u.total_volume_m3, 0) AS total_volume,
+			u.contract_id IS NOT NULL AS has_usage_data,
+			` + paceColumns + `,
 			c.start_at,
 			c.end_at,
 			c.is_active`).
 		Joins("LEFT JOIN contract_usage u ON u.contract_id = c.id").
 		Joins("LEFT JOIN organizations org ON org.id = c.contractor_id")
 
+	if hasPace {
+		pace := r.db.WithContext(ctx).
+			Table("mv_contract_daily mv").
+			Select("mv.contract_id, SUM(mv.total_trips) AS trips, COALESCE(SUM(mv.total_volume_m3), 0) AS volume").
+			Joins("JOIN contracts pc ON pc.id = mv.contract_id").
+			Where("mv.bucket >= date_trunc('day', pc.start_at) AND mv.bucket <= LEAST(pc.end_at, ?)", now).
+			Group("mv.contract_id")
+		query = query.Joins("LEFT JOIN (?) AS pace ON pace.contract_id = c.id", pace)
+	}
+
 	query = applyContractScope(query, scope)
 
 	if err := query.Scan(&rows).Error; err != nil {
@@ -412,7 +563,15 @@ func (r *AnalyticsRepository) ContractProgress(ctx context.Context, scope model.
 	contracts := make([]model.ContractProgress, 0, len(rows))
 	for _, row := range rows {
 		status := deriveContractStatus(row.StartAt, row.EndAt, now)
-		result := deriveContractResult(status, row.TotalVolume, row.MinimalVolume)
+		result := deriveContractResult(status, contractProgressInput{
+			start:         row.StartAt,
+			end:           row.EndAt,
+			now:           now,
+			totalVolume:   row.TotalVolume,
+			minimalVolume: row.MinimalVolume,
+			totalCost:     row.TotalCost,
+			budgetTotal:   row.BudgetTotal,
+		})
 		budgetProgress := 0.0
 		if row.BudgetTotal > 0 {
 			budgetProgress = row.TotalCost / row.BudgetTotal
@@ -421,32 +580,50 @@ func (r *AnalyticsRepository) ContractProgress(ctx context.Context, scope model.
 		if row.MinimalVolume > 0 {
 			volumeProgress = row.TotalVolume / row.MinimalVolume
 		}
+		avgTrips, avgVolume := 0.0, 0.0
+		if days := elapsedContractDays(row.StartAt, row.EndAt, now); days > 0 {
+			avgTrips = float64(row.PaceTrips) / days
+			avgVolume = row.PaceVolume / days
+		}
+		projectedCost := deriveProjectedCost(status, contractProgressInput{
+			start:     row.StartAt,
+			end:       row.EndAt,
+			now:       now,
+			totalCost: row.TotalCost,
+		})
 		contracts = append(contracts, model.ContractProgress{
-			ContractID:     row.ContractID,
-			Name:           row.Name,
-			ContractorID:   row.ContractorID,
-			ContractorName: row.ContractorName,
-			BudgetTotal:    row.BudgetTotal,
-			TotalCost:      row.TotalCost,
-			MinimalVolume:  row.MinimalVolume,
-			TotalVolume:    row.TotalVolume,
-			BudgetProgress: budgetProgress,
-			VolumeProgress: volumeProgress,
-			UIStatus:       status,
-			Result:         result,
-			StartAt:        row.StartAt,
-			EndAt:          row.EndAt,
+			ContractID:          row.ContractID,
+			Name:                row.Name,
+			ContractorID:        row.ContractorID,
+			ContractorName:      row.ContractorName,
+			BudgetTotal:         row.BudgetTotal,
+			TotalCost:           row.TotalCost,
+			MinimalVolume:       row.MinimalVolume,
+			TotalVolume:         row.TotalVolume,
+			BudgetProgress:      budgetProgress,
+			VolumeProgress:      volumeProgress,
+			HasUsageData:        row.HasUsageData,
+			UIStatus:            status,
+			Result:              result,
+			StartAt:             row.StartAt,
+			EndAt:               row.EndAt,
+			ProjectedEndCost:    projectedCost,
+			ProjectedOverBudget: projectedCost != nil && row.BudgetTotal > 0 && *projectedCost > row.BudgetTotal,
+			AvgTripsPerDay:      avgTrips,
+			AvgVolumePerDay:     avgVolume,
 		})
 	}
 
 	return contracts, nil
 }
 
-func (r *AnalyticsRepository) MapStates(ctx context.Context, scope model.Scope, rng model.DateRange) (areas []model.MapAreaState, polygons []model.MapPolygonState, cameras []model.MapCameraState, err error) {
+// MapStates returns the map overlays. A non-nil bbox keeps only areas and
+// polygons whose geometry intersects it; cameras are not filtered.
+func (r *AnalyticsRepository) MapStates(ctx context.Context, scope model.Scope, rng model.DateRange, activeSince time.Time, bbox *model.BBox) (areas []model.MapAreaState, polygons []model.MapPolygonState, cameras []model.MapCameraState, err error) {
 	if !r.tablesAvailable(ctx, "trips", "tickets") {
 		return nil, nil, nil, nil
 	}
-	areaActivity, err := r.CleaningAreaActivity(ctx, scope, rng)
+	areaActivity, err := r.CleaningAreaActivity(ctx, scope, rng, activeSince, bbox)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -480,6 +657,9 @@ func (r *AnalyticsRepository) MapStates(ctx context.Context, scope model.Scope,
 			Where("tr.polygon_id IS NOT NULL AND tr.entry_at BETWEEN ? AND ?", rng.From, rng.To).
 			Group("tr.polygon_id, p.name")
 
+		if bbox != nil && r.spatialAvailable(ctx, "polygons") {
+			polyQuery = applyBBox(polyQuery, "p.geometry", bbox)
+		}
 		polyQuery = applyTripScope(polyQuery, scope)
 
 		if err := polyQuery.Scan(&polygonRows).Error; err != nil {
@@ -522,14 +702,7 @@ func (r *AnalyticsRepository) MapStates(ctx context.Context, scope model.Scope,
 			GROUP BY camera_id
 		) AS errors ON errors.camera_id = c.id`, rng.From, rng.To)
 
-		if scope.Type != model.ScopeCity && scope.Type != model.ScopeTechnical {
-			cameraIDs := r.db.WithContext(ctx).
-				Table("trips tr").
-				Select("DISTINCT tr.camera_id").
-				Joins("JOIN tickets t ON t.id = tr.ticket_id").
-				Where("tr.camera_id IS NOT NULL").
-				Where("tr.entry_at BETWEEN ? AND ?", rng.From, rng.To)
-			cameraIDs = applyTripScope(cameraIDs, scope)
+		if cameraIDs := r.scopedCameraIDs(ctx, scope, rng); cameraIDs != nil {
 			cameraQuery = cameraQuery.Where("c.id IN (?)", cameraIDs)
 		}
 
@@ -549,163 +722,954 @@ func (r *AnalyticsRepository) MapStates(ctx context.Context, scope model.Scope,
 	return areas, polygons, cameras, nil
 }
 
+// KguComparison aggregates trips, volume and violations per KGU (the
+// organization that created the ticket), busiest first. It is meant for the
+// city scope and applies no org scope of its own.
+func (r *AnalyticsRepository) KguComparison(ctx context.Context, filter model.AnalyticsFilter) ([]model.KguComparison, error) {
+	if !r.relationExists(ctx, "mv_trip_daily") || !r.tablesAvailable(ctx, "organizations") {
+		return nil, nil
+	}
+
+	var rows []struct {
+		KguID      uuid.UUID
+		KguName    string
+		TripCount  int64
+		VolumeM3   float64
+		Violations int64
+	}
+
+	query := r.db.WithContext(ctx).
+		Table("mv_trip_daily mv").
+		Select(`mv.created_by_org_id AS kgu_id,
+			COALESCE(org.name, 'KGU') AS kgu_name,
+			SUM(mv.total_trips) AS trip_count,
+			COALESCE(SUM(mv.total_volume_m3), 0) AS volume_m3,
+			SUM(mv.violation_count) AS violations`).
+		Joins("LEFT JOIN organizations org ON org.id = mv.created_by_org_id").
+		Where("mv.created_by_org_id IS NOT NULL AND mv.bucket BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
+		Group("mv.created_by_org_id, org.name").
+		Order("trip_count DESC")
+
+	query = applyContractorFilter(query, "mv.contractor_id", filter)
+	if filter.CreatedByOrgID != nil {
+		query = query.Where("mv.created_by_org_id = ?", *filter.CreatedByOrgID)
+	}
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	total := float64(0)
+	for _, row := range rows {
+		total += float64(row.TripCount)
+	}
+
+	result := make([]model.KguComparison, 0, len(rows))
+	for _, row := range rows {
+		item := model.KguComparison{
+			KguID:      row.KguID,
+			KguName:    row.KguName,
+			TripCount:  row.TripCount,
+			VolumeM3:   row.VolumeM3,
+			Violations: row.Violations,
+		}
+		if row.TripCount > 0 {
+			item.ViolationRate = float64(row.Violations) / float64(row.TripCount)
+		}
+		if total > 0 {
+			item.TripShare = float64(row.TripCount) / total
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// MapAreaFeatures returns the cleaning areas with trips in rng as GeoJSON
+// features, limited to bbox when it is not nil. Areas without geometry are
+// skipped, and none are returned when PostGIS is not installed. Open trips
+// count as active the same way as in CleaningAreaActivity.
+func (r *AnalyticsRepository) MapAreaFeatures(ctx context.Context, scope model.Scope, rng model.DateRange, activeSince time.Time, bbox *model.BBox) ([]model.MapAreaFeature, error) {
+	if !r.tablesAvailable(ctx, "trips", "tickets", "cleaning_areas") || !r.functionExists(ctx, "st_asgeojson") {
+		return nil, nil
+	}
+
+	type row struct {
+		CleaningAreaID uuid.UUID
+		Name           string
+		Trips          int64
+		ActiveTrips    int64
+		Violations     int64
+		Geometry       string
+	}
+	var rows []row
+
+	activeExpr := "SUM(CASE WHEN tr.exit_at IS NULL THEN 1 ELSE 0 END) AS active_trips"
+	var activeArgs []interface{}
+	if !activeSince.IsZero() {
+		activeExpr = "SUM(CASE WHEN tr.exit_at IS NULL AND tr.entry_at >= ? THEN 1 ELSE 0 END) AS active_trips"
+		activeArgs = append(activeArgs, activeSince)
+	}
+
+	query := r.db.WithContext(ctx).
+		Table("trips tr").
+		Select(`t.cleaning_area_id AS cleaning_area_id,
+			COALESCE(ca.name, 'Cleaning area') AS name,
+			COUNT(*) AS trips,
+			`+activeExpr+`,
+			SUM(CASE WHEN tr.status <> 'OK' THEN 1 ELSE 0 END) AS violations,
+			ST_AsGeoJSON(ca.geometry)::text AS geometry`, activeArgs...).
+		Joins("JOIN tickets t ON t.id = tr.ticket_id").
+		Joins("JOIN cleaning_areas ca ON ca.id = t.cleaning_area_id").
+		Where("ca.geometry IS NOT NULL").
+		Where("tr.entry_at BETWEEN ? AND ?", rng.From, rng.To).
+		Group("t.cleaning_area_id, ca.name, ca.geometry")
+
+	query = applyBBox(query, "ca.geometry", bbox)
+	query = applyTripScope(query, scope)
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var maxTrips int64
+	for _, row := range rows {
+		if row.Trips > maxTrips {
+			maxTrips = row.Trips
+		}
+	}
+
+	result := make([]model.MapAreaFeature, 0, len(rows))
+	for _, row := range rows {
+		intensity := 0.0
+		if maxTrips > 0 {
+			intensity = float64(row.Trips) / float64(maxTrips)
+		}
+		result = append(result, model.MapAreaFeature{
+			Type:     "Feature",
+			ID:       row.CleaningAreaID,
+			Geometry: json.RawMessage(row.Geometry),
+			Properties: model.MapAreaProperties{
+				Name:        row.Name,
+				TripCount:   row.Trips,
+				ActiveTrips: row.ActiveTrips,
+				Violations:  row.Violations,
+				Intensity:   intensity,
+			},
+		})
+	}
+	return result, nil
+}
+
+// ContractSeries buckets mv_contract_daily for one contract. It returns
+// gorm.ErrRecordNotFound when the contract does not exist or is outside scope.
+func (r *AnalyticsRepository) ContractSeries(ctx context.Context, scope model.Scope, contractID uuid.UUID, filter model.AnalyticsFilter) ([]model.ContractSeriesPoint, error) {
+	if !r.tablesAvailable(ctx, "contracts") {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var found int64
+	query := r.db.WithContext(ctx).
+		Table("contracts c").
+		Where("c.id = ?", contractID)
+	query = applyContractScope(query, scope)
+	if err := query.Count(&found).Error; err != nil {
+		return nil, err
+	}
+	if found == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	points := []model.ContractSeriesPoint{}
+	if !r.relationExists(ctx, "mv_contract_daily") {
+		return points, nil
+	}
+
+	bucket, bucketArgs := bucketExpr("mv.bucket", filter)
+	err := r.db.WithContext(ctx).
+		Table("mv_contract_daily mv").
+		Select(fmt.Sprintf(`%s AS bucket,
+			SUM(mv.total_trips) AS trip_count,
+			COALESCE(SUM(mv.total_volume_m3), 0) AS total_volume,
+			SUM(mv.violation_count) AS violation_count`, bucket), bucketArgs...).
+		Where("mv.contract_id = ?", contractID).
+		Where("mv.bucket BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
+		Group("bucket").
+		Order("bucket ASC").
+		Scan(&points).Error
+	if err != nil {
+		return nil, err
+	}
+
+	loc := filter.Location()
+	for i := range points {
+		points[i].Bucket = points[i].Bucket.In(loc)
+	}
+	return points, nil
+}
+
 func (r *AnalyticsRepository) TripSeries(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter) ([]model.SeriesPoint, error) {
+	if filter.FromTrips() {
+		return r.tripIntervalSeries(ctx, scope, filter, false)
+	}
 	if !r.relationExists(ctx, "mv_trip_daily") {
 		return nil, nil
 	}
 
-	group := buildDateTrunc(filter.GroupBy)
+	bucket, bucketArgs := bucketExpr("mv.bucket", filter)
 	var rows []model.SeriesPoint
 
 	query := r.db.WithContext(ctx).
 		Table("mv_trip_daily mv").
-		Select(fmt.Sprintf("DATE_TRUNC('%s', mv.bucket) AS bucket, SUM(mv.total_trips) AS count", group)).
+		Select(fmt.Sprintf("%s AS bucket, SUM(mv.total_trips) AS count", bucket), bucketArgs...).
 		Where("mv.bucket BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
 		Group("bucket").
 		Order("bucket ASC")
 
-	if filter.ContractorID != nil {
-		query = query.Where("mv.contractor_id = ?", *filter.ContractorID)
-	}
+	query = applyContractorFilter(query, "mv.contractor_id", filter)
 	if filter.DriverID != nil {
 		query = query.Where("mv.driver_id = ?", *filter.DriverID)
 	}
+	if filter.VehicleID != nil {
+		query = query.Where("mv.vehicle_id = ?", *filter.VehicleID)
+	}
+	if filter.PolygonID != nil {
+		query = query.Where("mv.polygon_id = ?", *filter.PolygonID)
+	}
 
 	query = applyMVTripScope(query, scope)
 
 	if err := query.Scan(&rows).Error; err != nil {
 		return nil, err
 	}
-	return rows, nil
+	return markPartialBuckets(localizeBuckets(rows, filter), filter), nil
 }
 
 func (r *AnalyticsRepository) TripVolumeSeries(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter) ([]model.SeriesPoint, error) {
+	if filter.FromTrips() {
+		return r.tripIntervalSeries(ctx, scope, filter, true)
+	}
 	if !r.relationExists(ctx, "mv_trip_daily") {
 		return nil, nil
 	}
 
-	group := buildDateTrunc(filter.GroupBy)
+	bucket, bucketArgs := bucketExpr("mv.bucket", filter)
 	var rows []model.SeriesPoint
 
 	query := r.db.WithContext(ctx).
 		Table("mv_trip_daily mv").
-		Select(fmt.Sprintf("DATE_TRUNC('%s', mv.bucket) AS bucket, SUM(mv.total_trips) AS count, COALESCE(SUM(mv.total_volume_m3),0) AS value", group)).
+		Select(fmt.Sprintf("%s AS bucket, SUM(mv.total_trips) AS count, COALESCE(SUM(mv.total_volume_m3),0) AS value", bucket), bucketArgs...).
 		Where("mv.bucket BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
 		Group("bucket").
 		Order("bucket ASC")
 
-	if filter.ContractorID != nil {
-		query = query.Where("mv.contractor_id = ?", *filter.ContractorID)
-	}
+	query = applyContractorFilter(query, "mv.contractor_id", filter)
 	if filter.DriverID != nil {
 		query = query.Where("mv.driver_id = ?", *filter.DriverID)
 	}
+	if filter.VehicleID != nil {
+		query = query.Where("mv.vehicle_id = ?", *filter.VehicleID)
+	}
+	if filter.PolygonID != nil {
+		query = query.Where("mv.polygon_id = ?", *filter.PolygonID)
+	}
 
 	query = applyMVTripScope(query, scope)
 
 	if err := query.Scan(&rows).Error; err != nil {
 		return nil, err
 	}
-	return rows, nil
+	return markPartialBuckets(localizeBuckets(rows, filter), filter), nil
 }
 
-func (r *AnalyticsRepository) TopDrivers(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, limit int) ([]model.EntityMetric, error) {
-	if !r.tablesAvailable(ctx, "trips", "drivers", "tickets") {
+// tripIntervalSeries buckets trips by filter.Interval or by hour, or by
+// filter.GroupBy for a camera filter. The daily views are too coarse for
+// sub-day buckets and keep no camera, so this reads the trips table directly.
+func (r *AnalyticsRepository) tripIntervalSeries(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, withVolume bool) ([]model.SeriesPoint, error) {
+	if !r.tablesAvailable(ctx, "trips", "tickets") {
 		return nil, nil
 	}
 
-	var rows []struct {
-		ID     uuid.UUID
-		Name   string
-		Count  int64
-		Volume float64
+	value := "0"
+	if withVolume {
+		value = "COALESCE(SUM(tr.detected_volume_entry),0)"
 	}
 
+	bucket, bucketArgs := bucketExpr("tr.entry_at", filter)
+	var rows []model.SeriesPoint
 	query := r.db.WithContext(ctx).
 		Table("trips tr").
-		Select("tr.driver_id AS id, COALESCE(d.full_name, 'Driver') AS name, COUNT(*) AS count, COALESCE(SUM(tr.detected_volume_entry),0) AS volume").
-		Joins("LEFT JOIN drivers d ON d.id = tr.driver_id").
+		Select(fmt.Sprintf("%s AS bucket, COUNT(*) AS count, %s AS value", bucket, value), bucketArgs...).
 		Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id").
-		Where("tr.driver_id IS NOT NULL AND tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
-		Group("tr.driver_id, d.full_name").
-		Order("count DESC").
-		Limit(limit)
+		Where("tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
+		Group("bucket").
+		Order("bucket ASC")
+
+	query = applyContractorFilter(query, "t.contractor_id", filter)
+	if filter.DriverID != nil {
+		query = query.Where("tr.driver_id = ?", *filter.DriverID)
+	}
+	if filter.VehicleID != nil {
+		query = query.Where("tr.vehicle_id = ?", *filter.VehicleID)
+	}
+	if filter.PolygonID != nil {
+		query = query.Where("tr.polygon_id = ?", *filter.PolygonID)
+	}
+	if filter.CameraID != nil {
+		query = query.Where("tr.camera_id = ?", *filter.CameraID)
+	}
 
 	query = applyTripScope(query, scope)
 
 	if err := query.Scan(&rows).Error; err != nil {
 		return nil, err
 	}
+	return markPartialBuckets(localizeBuckets(rows, filter), filter), nil
+}
 
-	total := float64(0)
-	for _, row := range rows {
-		total += float64(row.Count)
+func (r *AnalyticsRepository) TripSeriesByEntity(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, limit int) (map[string][]model.SeriesPoint, error) {
+	column := entitySeriesColumn(filter.GroupByEntity)
+	if column == "" || !r.relationExists(ctx, "mv_trip_daily") {
+		return nil, nil
 	}
 
-	result := make([]model.EntityMetric, 0, len(rows))
+	bucket, bucketArgs := bucketExpr("mv.bucket", filter)
+	var rows []struct {
+		EntityID uuid.UUID
+		Bucket   time.Time
+		Count    int64
+		Value    float64
+	}
+
+	topEntities := r.db.WithContext(ctx).
+		Table("mv_trip_daily mv").
+		Select(column).
+		Where(column+" IS NOT NULL").
+		Where("mv.bucket BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
+		Group(column).
+		Order("SUM(mv.total_trips) DESC").
+		Limit(limit)
+
+	query := r.db.WithContext(ctx).
+		Table("mv_trip_daily mv").
+		Select(fmt.Sprintf("%s AS entity_id, %s AS bucket, SUM(mv.total_trips) AS count, COALESCE(SUM(mv.total_volume_m3),0) AS value", column, bucket), bucketArgs...).
+		Where("mv.bucket BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
+		Group("entity_id, bucket").
+		Order("bucket ASC")
+
+	topEntities = applyContractorFilter(topEntities, "mv.contractor_id", filter)
+	query = applyContractorFilter(query, "mv.contractor_id", filter)
+	if filter.DriverID != nil {
+		topEntities = topEntities.Where("mv.driver_id = ?", *filter.DriverID)
+		query = query.Where("mv.driver_id = ?", *filter.DriverID)
+	}
+	if filter.VehicleID != nil {
+		topEntities = topEntities.Where("mv.vehicle_id = ?", *filter.VehicleID)
+		query = query.Where("mv.vehicle_id = ?", *filter.VehicleID)
+	}
+	if filter.PolygonID != nil {
+		topEntities = topEntities.Where("mv.polygon_id = ?", *filter.PolygonID)
+		query = query.Where("mv.polygon_id = ?", *filter.PolygonID)
+	}
+
+	topEntities = applyMVTripScope(topEntities, scope)
+	query = applyMVTripScope(query, scope)
+	query = query.Where(column+" IN (?)", topEntities)
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]model.SeriesPoint)
 	for _, row := range rows {
-		share := 0.0
-		if total > 0 {
-			share = float64(row.Count) / total
-		}
-		result = append(result, model.EntityMetric{
-			ID:     row.ID,
-			Name:   row.Name,
+		key := row.EntityID.String()
+		result[key] = append(result[key], model.SeriesPoint{
+			Bucket: row.Bucket.In(filter.Location()),
 			Count:  row.Count,
-			Volume: row.Volume,
-			Share:  share,
+			Value:  row.Value,
 		})
 	}
 	return result, nil
 }
 
-func (r *AnalyticsRepository) TopContractors(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, limit int) ([]model.EntityMetric, error) {
-	if !r.tablesAvailable(ctx, "trips", "tickets", "organizations") {
-		return nil, nil
+func (r *AnalyticsRepository) TripStatusSeries(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter) (model.TripStatusSeries, error) {
+	result := model.TripStatusSeries{Buckets: []time.Time{}, Statuses: []model.StatusSeriesLine{}}
+	if !r.tablesAvailable(ctx, "trips", "tickets") {
+		return result, nil
 	}
 
+	bucket, bucketArgs := bucketExpr("tr.entry_at", filter)
 	var rows []struct {
-		ID     uuid.UUID
-		Name   string
+		Bucket time.Time
+		Status string
 		Count  int64
-		Volume float64
 	}
 
 	query := r.db.WithContext(ctx).
 		Table("trips tr").
-		Select("t.contractor_id AS id, COALESCE(org.name, 'Contractor') AS name, COUNT(*) AS count, COALESCE(SUM(tr.detected_volume_entry),0) AS volume").
+		Select(fmt.Sprintf("%s AS bucket, tr.status::text AS status, COUNT(*) AS count", bucket), bucketArgs...).
 		Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id").
-		Joins("LEFT JOIN organizations org ON org.id = t.contractor_id").
-		Where("t.contractor_id IS NOT NULL AND tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
-		Group("t.contractor_id, org.name").
-		Order("count DESC").
-		Limit(limit)
+		Where("tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
+		Group("bucket, tr.status").
+		Order("bucket ASC, status ASC")
+
+	query = applyContractorFilter(query, "t.contractor_id", filter)
+	if filter.DriverID != nil {
+		query = query.Where("tr.driver_id = ?", *filter.DriverID)
+	}
+	if filter.VehicleID != nil {
+		query = query.Where("tr.vehicle_id = ?", *filter.VehicleID)
+	}
+	if filter.PolygonID != nil {
+		query = query.Where("tr.polygon_id = ?", *filter.PolygonID)
+	}
+	if filter.CameraID != nil {
+		query = query.Where("tr.camera_id = ?", *filter.CameraID)
+	}
 
 	query = applyTripScope(query, scope)
 
 	if err := query.Scan(&rows).Error; err != nil {
-		return nil, err
+		return result, err
 	}
 
-	total := float64(0)
+	bucketIndex := make(map[int64]int)
 	for _, row := range rows {
-		total += float64(row.Count)
+		key := row.Bucket.UnixNano()
+		if _, ok := bucketIndex[key]; !ok {
+			bucketIndex[key] = len(result.Buckets)
+			result.Buckets = append(result.Buckets, row.Bucket.In(filter.Location()))
+		}
 	}
 
-	result := make([]model.EntityMetric, 0, len(rows))
+	statusIndex := make(map[string]int)
 	for _, row := range rows {
-		share := 0.0
-		if total > 0 {
-			share = float64(row.Count) / total
+		idx, ok := statusIndex[row.Status]
+		if !ok {
+			idx = len(result.Statuses)
+			statusIndex[row.Status] = idx
+			result.Statuses = append(result.Statuses, model.StatusSeriesLine{
+				Status: row.Status,
+				Counts: make([]int64, len(result.Buckets)),
+			})
+		}
+		result.Statuses[idx].Counts[bucketIndex[row.Bucket.UnixNano()]] = row.Count
+	}
+
+	return result, nil
+}
+
+// DriverCountSeries counts distinct drivers per bucket. mv_trip_daily keeps
+// driver_id as a dimension, so distinct counts over day/week/month buckets
+// are exact; interval and hourly buckets are finer than a day and, like
+// camera filtered series, read the trips table.
+func (r *AnalyticsRepository) DriverCountSeries(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, limit int) (model.DriverCountSeries, error) {
+	result := model.DriverCountSeries{Total: []model.SeriesPoint{}, Contractors: []model.ContractorDriverSeries{}}
+
+	var (
+		bucket, bucketArgs = bucketExpr("mv.bucket", filter)
+		contractorColumn   = "mv.contractor_id"
+		driverColumn       = "mv.driver_id"
+		newQuery           func() *gorm.DB
+	)
+	if filter.FromTrips() {
+		if !r.tablesAvailable(ctx, "trips", "tickets") {
+			return result, nil
 		}
+		bucket, bucketArgs = bucketExpr("tr.entry_at", filter)
+		contractorColumn = "t.contractor_id"
+		driverColumn = "tr.driver_id"
+		newQuery = func() *gorm.DB {
+			query := r.db.WithContext(ctx).
+				Table("trips tr").
+				Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id").
+				Where("tr.driver_id IS NOT NULL AND tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To)
+			query = applyContractorFilter(query, "t.contractor_id", filter)
+			if filter.DriverID != nil {
+				query = query.Where("tr.driver_id = ?", *filter.DriverID)
+			}
+			if filter.VehicleID != nil {
+				query = query.Where("tr.vehicle_id = ?", *filter.VehicleID)
+			}
+			if filter.PolygonID != nil {
+				query = query.Where("tr.polygon_id = ?", *filter.PolygonID)
+			}
+			if filter.CameraID != nil {
+				query = query.Where("tr.camera_id = ?", *filter.CameraID)
+			}
+			return applyTripScope(query, scope)
+		}
+	} else {
+		if !r.relationExists(ctx, "mv_trip_daily") {
+			return result, nil
+		}
+		newQuery = func() *gorm.DB {
+			query := r.db.WithContext(ctx).
+				Table("mv_trip_daily mv").
+				Where("mv.driver_id IS NOT NULL AND mv.bucket BETWEEN ? AND ?", filter.Range.From, filter.Range.To)
+			query = applyContractorFilter(query, "mv.contractor_id", filter)
+			if filter.DriverID != nil {
+				query = query.Where("mv.driver_id = ?", *filter.DriverID)
+			}
+			if filter.VehicleID != nil {
+				query = query.Where("mv.vehicle_id = ?", *filter.VehicleID)
+			}
+			if filter.PolygonID != nil {
+				query = query.Where("mv.polygon_id = ?", *filter.PolygonID)
+			}
+			return applyMVTripScope(query, scope)
+		}
+	}
+
+	var total []model.SeriesPoint
+	err := newQuery().
+		Select(fmt.Sprintf("%s AS bucket, COUNT(DISTINCT %s) AS count", bucket, driverColumn), bucketArgs...).
+		Group("bucket").
+		Order("bucket ASC").
+		Scan(&total).Error
+	if err != nil {
+		return result, err
+	}
+	result.Total = localizeBuckets(total, filter)
+
+	topContractors := newQuery().
+		Select(contractorColumn).
+		Where(contractorColumn + " IS NOT NULL").
+		Group(contractorColumn).
+		Order(fmt.Sprintf("COUNT(DISTINCT %s) DESC", driverColumn)).
+		Limit(limit)
+
+	var rows []struct {
+		ContractorID   uuid.UUID
+		ContractorName string
+		Bucket         time.Time
+		Count          int64
+	}
+	err = newQuery().
+		Select(fmt.Sprintf("%s AS contractor_id, COALESCE(org.name, 'Contractor') AS contractor_name, %s AS bucket, COUNT(DISTINCT %s) AS count", contractorColumn, bucket, driverColumn), bucketArgs...).
+		Joins(fmt.Sprintf("LEFT JOIN organizations org ON org.id = %s", contractorColumn)).
+		Where(contractorColumn+" IN (?)", topContractors).
+		Group(fmt.Sprintf("%s, org.name, bucket", contractorColumn)).
+		Order("contractor_name ASC, bucket ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return result, err
+	}
+
+	index := make(map[uuid.UUID]int)
+	for _, row := range rows {
+		idx, ok := index[row.ContractorID]
+		if !ok {
+			idx = len(result.Contractors)
+			index[row.ContractorID] = idx
+			result.Contractors = append(result.Contractors, model.ContractorDriverSeries{
+				ContractorID:   row.ContractorID,
+				ContractorName: row.ContractorName,
+			})
+		}
+		result.Contractors[idx].Series = append(result.Contractors[idx].Series, model.SeriesPoint{
+			Bucket: row.Bucket.In(filter.Location()),
+			Count:  row.Count,
+		})
+	}
+
+	return result, nil
+}
+
+// ContractorRankSeries ranks contractorID among the contractors sharing its
+// parent organization per bucket, by trip count or volume. Buckets in which
+// the contractor had no trips are omitted.
+func (r *AnalyticsRepository) ContractorRankSeries(ctx context.Context, contractorID uuid.UUID, filter model.AnalyticsFilter, byVolume bool) ([]model.ContractorRankPoint, error) {
+	if !r.tablesAvailable(ctx, "mv_trip_daily", "organizations") {
+		return []model.ContractorRankPoint{}, nil
+	}
+
+	metric := "SUM(mv.total_trips)::float8"
+	if byVolume {
+		metric = "COALESCE(SUM(mv.total_volume_m3), 0)::float8"
+	}
+	bucket, bucketArgs := bucketExpr("mv.bucket", filter)
+	// A city user's created_by_org_id ranks the peers on that KGU's tickets.
+	createdBy := ""
+	if filter.CreatedByOrgID != nil {
+		createdBy = "AND mv.created_by_org_id = ?"
+	}
+
+	sql := fmt.Sprintf(`
+		WITH peers AS (
+			SELECT o.id
+			FROM organizations o
+			WHERE o.type = ?
+				AND o.parent_org_id IS NOT DISTINCT FROM (SELECT parent_org_id FROM organizations WHERE id = ?)
+		),
+		totals AS (
+			SELECT %s AS bucket, mv.contractor_id, %s AS value
+			FROM mv_trip_daily mv
+			WHERE mv.bucket BETWEEN ? AND ?
+				AND mv.contractor_id IN (SELECT id FROM peers)
+				%s
+			GROUP BY 1, mv.contractor_id
+		),
+		ranked AS (
+			SELECT bucket, contractor_id, value,
+				RANK() OVER (PARTITION BY bucket ORDER BY value DESC) AS rank,
+				COUNT(*) OVER (PARTITION BY bucket) AS peers
+			FROM totals
+		)
+		SELECT bucket, rank, peers, value
+		FROM ranked
+		WHERE contractor_id = ?
+		ORDER BY bucket ASC`, bucket, metric, createdBy)
+
+	args := []interface{}{orgTypeContractor, contractorID}
+	args = append(args, bucketArgs...)
+	args = append(args, filter.Range.From, filter.Range.To)
+	if filter.CreatedByOrgID != nil {
+		args = append(args, *filter.CreatedByOrgID)
+	}
+	args = append(args, contractorID)
+
+	rows := make([]model.ContractorRankPoint, 0)
+	if err := r.db.WithContext(ctx).Raw(sql, args...).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	loc := filter.Location()
+	for i := range rows {
+		rows[i].Bucket = rows[i].Bucket.In(loc)
+	}
+	return rows, nil
+}
+
+// PeakHours returns the busiest hours of day (0-23, in the filter's time
+// zone) by trip count.
+func (r *AnalyticsRepository) PeakHours(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, limit int) ([]model.PeakHour, error) {
+	if !r.tablesAvailable(ctx, "trips", "tickets") {
+		return []model.PeakHour{}, nil
+	}
+
+	rows := make([]model.PeakHour, 0, limit)
+	query := r.db.WithContext(ctx).
+		Table("trips tr").
+		Select("EXTRACT(HOUR FROM tr.entry_at AT TIME ZONE ?)::int AS hour, COUNT(*) AS trip_count", filter.Location().String()).
+		Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id").
+		Where("tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
+		Group("hour").
+		Order("trip_count DESC, hour ASC").
+		Limit(limit)
+
+	query = applyContractorFilter(query, "t.contractor_id", filter)
+	if filter.DriverID != nil {
+		query = query.Where("tr.driver_id = ?", *filter.DriverID)
+	}
+	if filter.VehicleID != nil {
+		query = query.Where("tr.vehicle_id = ?", *filter.VehicleID)
+	}
+	if filter.PolygonID != nil {
+		query = query.Where("tr.polygon_id = ?", *filter.PolygonID)
+	}
+	if filter.CameraID != nil {
+		query = query.Where("tr.camera_id = ?", *filter.CameraID)
+	}
+
+	query = applyTripScope(query, scope)
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// TripHeatmap counts trips per day of week and hour of day in the filter's
+// time zone.
+func (r *AnalyticsRepository) TripHeatmap(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter) (model.TripHeatmap, error) {
+	heatmap := model.TripHeatmap{
+		Matrix:       make([][]int64, 7),
+		RowTotals:    make([]int64, 7),
+		ColumnTotals: make([]int64, 24),
+	}
+	for day := range heatmap.Matrix {
+		heatmap.Matrix[day] = make([]int64, 24)
+	}
+	if !r.tablesAvailable(ctx, "trips", "tickets") {
+		return heatmap, nil
+	}
+
+	type row struct {
+		Dow       int
+		Hour      int
+		TripCount int64
+	}
+	var rows []row
+
+	tz := filter.Location().String()
+	query := r.db.WithContext(ctx).
+		Table("trips tr").
+		Select(`EXTRACT(DOW FROM tr.entry_at AT TIME ZONE ?)::int AS dow,
+			EXTRACT(HOUR FROM tr.entry_at AT TIME ZONE ?)::int AS hour,
+			COUNT(*) AS trip_count`, tz, tz).
+		Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id").
+		Where("tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
+		Group("dow, hour")
+
+	query = applyContractorFilter(query, "t.contractor_id", filter)
+	if filter.DriverID != nil {
+		query = query.Where("tr.driver_id = ?", *filter.DriverID)
+	}
+	if filter.VehicleID != nil {
+		query = query.Where("tr.vehicle_id = ?", *filter.VehicleID)
+	}
+	if filter.PolygonID != nil {
+		query = query.Where("tr.polygon_id = ?", *filter.PolygonID)
+	}
+	if filter.CameraID != nil {
+		query = query.Where("tr.camera_id = ?", *filter.CameraID)
+	}
+
+	query = applyTripScope(query, scope)
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return heatmap, err
+	}
+	for _, row := range rows {
+		if row.Dow < 0 || row.Dow > 6 || row.Hour < 0 || row.Hour > 23 {
+			continue
+		}
+		heatmap.Matrix[row.Dow][row.Hour] = row.TripCount
+		heatmap.RowTotals[row.Dow] += row.TripCount
+		heatmap.ColumnTotals[row.Hour] += row.TripCount
+		heatmap.Total += row.TripCount
+	}
+	return heatmap, nil
+}
+
+// fillRateBounds are the lower bounds of the fill-rate buckets; the last one
+// is open-ended.
+var fillRateBounds = []float64{0, 0.25, 0.5, 0.75, 1}
+
+// FillRateDistribution buckets trips by detected_volume_entry over the
+// vehicle body volume. Trips without an entry reading or of vehicles without
+// a positive body volume are left out.
+func (r *AnalyticsRepository) FillRateDistribution(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter) (model.FillRateDistribution, error) {
+	distribution := model.FillRateDistribution{Range: filter.Range, Buckets: make([]model.FillRateBucket, len(fillRateBounds))}
+	for i, from := range fillRateBounds {
+		bucket := model.FillRateBucket{From: from}
+		if i+1 < len(fillRateBounds) {
+			to := fillRateBounds[i+1]
+			bucket.To = &to
+			bucket.Label = fmt.Sprintf("%.0f-%.0f%%", from*100, to*100)
+		} else {
+			bucket.Label = fmt.Sprintf(">%.0f%%", from*100)
+		}
+		distribution.Buckets[i] = bucket
+	}
+	if !r.tablesAvailable(ctx, "trips", "vehicles", "tickets") {
+		return distribution, nil
+	}
+
+	type row struct {
+		Bucket    int
+		TripCount int64
+	}
+	var rows []row
+
+	query := r.db.WithContext(ctx).
+		Table("trips tr").
+		Select(`CASE
+				WHEN tr.detected_volume_entry / v.body_volume_m3 < 0.25 THEN 0
+				WHEN tr.detected_volume_entry / v.body_volume_m3 < 0.5 THEN 1
+				WHEN tr.detected_volume_entry / v.body_volume_m3 < 0.75 THEN 2
+				WHEN tr.detected_volume_entry / v.body_volume_m3 <= 1 THEN 3
+				ELSE 4
+			END AS bucket,
+			COUNT(*) AS trip_count`).
+		Joins("JOIN vehicles v ON v.id = tr.vehicle_id").
+		Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id").
+		Where("v.body_volume_m3 > 0 AND tr.detected_volume_entry IS NOT NULL").
+		Where("tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
+		Group("bucket")
+
+	query = applyContractorFilter(query, "t.contractor_id", filter)
+	if filter.DriverID != nil {
+		query = query.Where("tr.driver_id = ?", *filter.DriverID)
+	}
+	if filter.VehicleID != nil {
+		query = query.Where("tr.vehicle_id = ?", *filter.VehicleID)
+	}
+	if filter.PolygonID != nil {
+		query = query.Where("tr.polygon_id = ?", *filter.PolygonID)
+	}
+	if filter.CameraID != nil {
+		query = query.Where("tr.camera_id = ?", *filter.CameraID)
+	}
+
+	query = applyTripScope(query, scope)
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return distribution, err
+	}
+	for _, row := range rows {
+		if row.Bucket < 0 || row.Bucket >= len(distribution.Buckets) {
+			continue
+		}
+		distribution.Buckets[row.Bucket].TripCount = row.TripCount
+		distribution.Total += row.TripCount
+	}
+	if distribution.Total > 0 {
+		for i := range distribution.Buckets {
+			distribution.Buckets[i].Share = float64(distribution.Buckets[i].TripCount) / float64(distribution.Total)
+		}
+	}
+	return distribution, nil
+}
+
+// leaderOrder ranks trip leaders by rankBy (model.RankByCount or
+// model.RankByVolume), breaking ties by the other metric.
+func leaderOrder(rankBy string) string {
+	if rankBy == model.RankByVolume {
+		return "volume DESC, count DESC"
+	}
+	return "count DESC"
+}
+
+// setLeaderShares sets each leader's share of the ranked metric over the
+// returned rows.
+func setLeaderShares(items []model.EntityMetric, rankBy string) {
+	metric := func(item model.EntityMetric) float64 {
+		if rankBy == model.RankByVolume {
+			return item.Volume
+		}
+		return float64(item.Count)
+	}
+	total := 0.0
+	for _, item := range items {
+		total += metric(item)
+	}
+	if total <= 0 {
+		return
+	}
+	for i := range items {
+		items[i].Share = metric(items[i]) / total
+	}
+}
+
+// TopDrivers ranks drivers by rankBy; Share is relative to the ranked metric.
+func (r *AnalyticsRepository) TopDrivers(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, limit int, rankBy string) ([]model.EntityMetric, error) {
+	if !r.tablesAvailable(ctx, "trips", "drivers", "tickets") {
+		return nil, nil
+	}
+
+	var rows []struct {
+		ID     uuid.UUID
+		Name   string
+		Count  int64
+		Volume float64
+	}
+
+	query := r.db.WithContext(ctx).
+		Table("trips tr").
+		Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id").
+		Where("tr.driver_id IS NOT NULL AND tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
+		Order(leaderOrder(rankBy)).
+		Limit(limit)
+	if r.names != nil {
+		query = query.
+			Select("tr.driver_id AS id, COUNT(*) AS count, COALESCE(SUM(tr.detected_volume_entry),0) AS volume").
+			Group("tr.driver_id")
+	} else {
+		query = query.
+			Select("tr.driver_id AS id, COALESCE(d.full_name, 'Driver') AS name, COUNT(*) AS count, COALESCE(SUM(tr.detected_volume_entry),0) AS volume").
+			Joins("LEFT JOIN drivers d ON d.id = tr.driver_id").
+			Group("tr.driver_id, d.full_name")
+	}
+
+	query = applyContractorFilter(query, "t.contractor_id", filter)
+	if filter.PolygonID != nil {
+		query = query.Where("tr.polygon_id = ?", *filter.PolygonID)
+	}
+	if filter.CameraID != nil {
+		query = query.Where("tr.camera_id = ?", *filter.CameraID)
+	}
+	query = applyTripScope(query, scope)
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]model.EntityMetric, 0, len(rows))
+	for _, row := range rows {
 		result = append(result, model.EntityMetric{
 			ID:     row.ID,
 			Name:   row.Name,
 			Count:  row.Count,
 			Volume: row.Volume,
-			Share:  share,
 		})
 	}
+	setLeaderShares(result, rankBy)
+	if r.names != nil {
+		if err := r.fillNames(ctx, driverNames, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// TopContractors ranks contractors like TopDrivers.
+func (r *AnalyticsRepository) TopContractors(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, limit int, rankBy string) ([]model.EntityMetric, error) {
+	if !r.tablesAvailable(ctx, "trips", "tickets", "organizations") {
+		return nil, nil
+	}
+
+	var rows []struct {
+		ID     uuid.UUID
+		Name   string
+		Count  int64
+		Volume float64
+	}
+
+	query := r.db.WithContext(ctx).
+		Table("trips tr").
+		Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id").
+		Where("t.contractor_id IS NOT NULL AND tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
+		Order(leaderOrder(rankBy)).
+		Limit(limit)
+	if r.names != nil {
+		query = query.
+			Select("t.contractor_id AS id, COUNT(*) AS count, COALESCE(SUM(tr.detected_volume_entry),0) AS volume").
+			Group("t.contractor_id")
+	} else {
+		query = query.
+			Select("t.contractor_id AS id, COALESCE(org.name, 'Contractor') AS name, COUNT(*) AS count, COALESCE(SUM(tr.detected_volume_entry),0) AS volume").
+			Joins("LEFT JOIN organizations org ON org.id = t.contractor_id").
+			Group("t.contractor_id, org.name")
+	}
+
+	query = applyContractorFilter(query, "t.contractor_id", filter)
+	if filter.PolygonID != nil {
+		query = query.Where("tr.polygon_id = ?", *filter.PolygonID)
+	}
+	if filter.CameraID != nil {
+		query = query.Where("tr.camera_id = ?", *filter.CameraID)
+	}
+	query = applyTripScope(query, scope)
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]model.EntityMetric, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, model.EntityMetric{
+			ID:     row.ID,
+			Name:   row.Name,
+			Count:  row.Count,
+			Volume: row.Volume,
+		})
+	}
+	setLeaderShares(result, rankBy)
+	if r.names != nil {
+		if err := r.fillNames(ctx, contractorNames, result); err != nil {
+			return nil, err
+		}
+	}
 	return result, nil
 }
 
@@ -716,14 +1680,25 @@ func (r *AnalyticsRepository) TripDurationStats(ctx context.Context, scope model
 
 	var stats model.TripDurationStats
 
+	minutes := "EXTRACT(EPOCH FROM (COALESCE(tr.exit_at, tr.entry_at) - tr.entry_at)) / 60"
 	query := r.db.WithContext(ctx).
 		Table("trips tr").
-		Select(`
-			COALESCE(AVG(EXTRACT(EPOCH FROM (COALESCE(tr.exit_at, tr.entry_at) - tr.entry_at)) / 60), 0) AS avg_minutes,
-			COALESCE(percentile_disc(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (COALESCE(tr.exit_at, tr.entry_at) - tr.entry_at)) / 60), 0) AS p95_minutes`).
+		Select(fmt.Sprintf(`
+			COALESCE(AVG(%[1]s), 0) AS avg_minutes,
+			COALESCE(percentile_disc(0.5) WITHIN GROUP (ORDER BY %[1]s), 0) AS p50_minutes,
+			COALESCE(percentile_disc(0.9) WITHIN GROUP (ORDER BY %[1]s), 0) AS p90_minutes,
+			COALESCE(percentile_disc(0.95) WITHIN GROUP (ORDER BY %[1]s), 0) AS p95_minutes,
+			COALESCE(MAX(%[1]s), 0) AS max_minutes`, minutes)).
 		Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id").
 		Where("tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To)
 
+	if filter.PolygonID != nil {
+		query = query.Where("tr.polygon_id = ?", *filter.PolygonID)
+	}
+	if filter.CameraID != nil {
+		query = query.Where("tr.camera_id = ?", *filter.CameraID)
+	}
+
 	query = applyTripScope(query, scope)
 
 	if err := query.Scan(&stats).Error; err != nil {
@@ -731,7 +1706,10 @@ func (r *AnalyticsRepository) TripDurationStats(ctx context.Context, scope model
 	}
 
 	stats.AvgMinutes = clamp(stats.AvgMinutes)
+	stats.P50Minutes = clamp(stats.P50Minutes)
+	stats.P90Minutes = clamp(stats.P90Minutes)
 	stats.P95Minutes = clamp(stats.P95Minutes)
+	stats.MaxMinutes = clamp(stats.MaxMinutes)
 	return stats, nil
 }
 
@@ -747,10 +1725,26 @@ func (r *AnalyticsRepository) TripVolumeStats(ctx context.Context, scope model.S
 		Select(`
 			COALESCE(AVG(tr.detected_volume_entry), 0) AS avg_volume,
 			COALESCE(MAX(tr.detected_volume_entry), 0) AS max_volume,
-			COALESCE(MIN(tr.detected_volume_entry), 0) AS min_volume`).
+			COALESCE(MIN(tr.detected_volume_entry), 0) AS min_volume,
+			COALESCE(AVG(tr.detected_volume_exit), 0) AS avg_exit_volume,
+			COALESCE(MAX(tr.detected_volume_exit), 0) AS max_exit_volume,
+			COALESCE(MIN(tr.detected_volume_exit), 0) AS min_exit_volume,
+			COALESCE(SUM(tr.detected_volume_entry), 0) AS total_volume,
+			COALESCE(SUM(tr.detected_volume_exit), 0) AS total_exit_volume,
+			COUNT(tr.detected_volume_entry) AS entry_readings,
+			COUNT(tr.detected_volume_exit) AS exit_readings,
+			COALESCE(AVG(tr.detected_volume_entry - tr.detected_volume_exit), 0) AS avg_net_volume,
+			COALESCE(SUM(tr.detected_volume_entry - tr.detected_volume_exit), 0) AS total_net_volume`).
 		Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id").
 		Where("tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To)
 
+	if filter.PolygonID != nil {
+		query = query.Where("tr.polygon_id = ?", *filter.PolygonID)
+	}
+	if filter.CameraID != nil {
+		query = query.Where("tr.camera_id = ?", *filter.CameraID)
+	}
+
 	query = applyTripScope(query, scope)
 
 	if err := query.Scan(&stats).Error; err != nil {
@@ -843,10 +1837,198 @@ func (r *AnalyticsRepository) TripDetails(ctx context.Context, scope model.Scope
 	}
 
 	result.Events = r.resolveTripEvents(ctx, details.EntryLprID, details.ExitLprID, details.EntryVolID, details.ExitVolID)
+	result.Violations = tripViolations(result)
 
 	return result, nil
 }
 
+// Violation record sources.
+const (
+	violationSourceTrip   = "TRIP"
+	violationSourceVolume = "VOLUME"
+)
+
+// violationExitExceedsEntry flags a trip that left with more snow than it
+// brought in, which points at a misread volume event.
+const violationExitExceedsEntry = "EXIT_VOLUME_EXCEEDS_ENTRY"
+
+// tripViolations derives the violation records of a trip. There is no
+// violations table: a non-OK status is the trip's violation, and the volume
+// readings are checked for anomalies the status does not capture.
+func tripViolations(trip *model.TripDetails) []model.ViolationRecord {
+	records := make([]model.ViolationRecord, 0, 2)
+	if trip.Status != model.TripStatusOK {
+		records = append(records, model.ViolationRecord{
+			Type:   trip.Status,
+			Source: violationSourceTrip,
+			At:     trip.EntryAt,
+		})
+	}
+
+	if trip.DetectedVolumeEntry != nil && trip.DetectedVolumeExit != nil && *trip.DetectedVolumeExit > *trip.DetectedVolumeEntry {
+		at := trip.EntryAt
+		if trip.Events.ExitVolume != nil {
+			at = trip.Events.ExitVolume.Captured
+		} else if trip.ExitAt != nil {
+			at = *trip.ExitAt
+		}
+		note := fmt.Sprintf("exit volume %.2f m3 exceeds entry volume %.2f m3", *trip.DetectedVolumeExit, *trip.DetectedVolumeEntry)
+		records = append(records, model.ViolationRecord{
+			Type:   violationExitExceedsEntry,
+			Source: violationSourceVolume,
+			At:     at,
+			Note:   &note,
+		})
+	}
+	return records
+}
+
+// ListTrips returns a page of the trips matching filter, newest first,
+// together with the total number of matches and the cursor of the last
+// item when more trips follow. With page.After set the page starts after
+// that trip and page.Offset is ignored, so deep pages cost no offset scan.
+func (r *AnalyticsRepository) ListTrips(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, page model.Pagination) ([]model.TripListItem, int64, *model.TripCursor, error) {
+	if !r.tablesAvailable(ctx, "trips", "tickets") {
+		return []model.TripListItem{}, 0, nil, nil
+	}
+
+	query := r.tripListQuery(ctx, scope, filter).
+		Where("tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, nil, err
+	}
+
+	items := make([]model.TripListItem, 0, page.Limit+1)
+	if total == 0 {
+		return items, 0, nil, nil
+	}
+
+	if page.After != nil {
+		query = query.Where("(tr.entry_at, tr.id) < (?, ?)", page.After.EntryAt, page.After.ID)
+	} else {
+		query = query.Offset(page.Offset)
+	}
+	// One extra row tells whether another page follows.
+	if err := selectTripListItems(query).Limit(page.Limit + 1).Scan(&items).Error; err != nil {
+		return nil, 0, nil, err
+	}
+
+	var next *model.TripCursor
+	if len(items) > page.Limit {
+		items = items[:page.Limit]
+		last := items[len(items)-1]
+		next = &model.TripCursor{EntryAt: last.EntryAt, ID: last.TripID}
+	}
+	return items, total, next, nil
+}
+
+// LatestTrips returns the n most recent trips matching filter, whatever
+// their date; filter.Range is ignored.
+func (r *AnalyticsRepository) LatestTrips(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, n int) ([]model.TripListItem, error) {
+	items := make([]model.TripListItem, 0, n)
+	if !r.tablesAvailable(ctx, "trips", "tickets") {
+		return items, nil
+	}
+	if err := selectTripListItems(r.tripListQuery(ctx, scope, filter)).Limit(n).Scan(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// tripListQuery applies the trip list filters except the date range.
+func (r *AnalyticsRepository) tripListQuery(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).
+		Table("trips tr").
+		Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id")
+
+	query = applyContractorFilter(query, "t.contractor_id", filter)
+	if filter.DriverID != nil {
+		query = query.Where("tr.driver_id = ?", *filter.DriverID)
+	}
+	if filter.VehicleID != nil {
+		query = query.Where("tr.vehicle_id = ?", *filter.VehicleID)
+	}
+	if filter.PolygonID != nil {
+		query = query.Where("tr.polygon_id = ?", *filter.PolygonID)
+	}
+	if filter.CameraID != nil {
+		query = query.Where("tr.camera_id = ?", *filter.CameraID)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("tr.status::text IN ?", filter.Statuses)
+	}
+
+	return applyTripScope(query, scope)
+}
+
+// selectTripListItems selects the trip list columns, newest first.
+func selectTripListItems(query *gorm.DB) *gorm.DB {
+	return query.
+		Select(`tr.id AS trip_id,
+			tr.status,
+			tr.entry_at,
+			tr.exit_at,
+			tr.driver_id,
+			d.full_name AS driver_name,
+			t.contractor_id,
+			org.name AS contractor_name,
+			tr.detected_volume_entry AS volume_entry,
+			tr.detected_volume_exit AS volume_exit`).
+		Joins("LEFT JOIN drivers d ON d.id = tr.driver_id").
+		Joins("LEFT JOIN organizations org ON org.id = t.contractor_id").
+		Order("tr.entry_at DESC, tr.id DESC")
+}
+
+// CameraEvents pages through the raw LPR and volume events of one camera,
+// newest first. It returns gorm.ErrRecordNotFound for unknown cameras.
+func (r *AnalyticsRepository) CameraEvents(ctx context.Context, cameraID uuid.UUID, rng model.DateRange, page model.Pagination) ([]model.CameraEvent, int64, error) {
+	if !r.tablesAvailable(ctx, "cameras", "lpr_events", "volume_events") {
+		return nil, 0, gorm.ErrRecordNotFound
+	}
+
+	var cameras int64
+	if err := r.db.WithContext(ctx).Table("cameras").Where("id = ?", cameraID).Count(&cameras).Error; err != nil {
+		return nil, 0, err
+	}
+	if cameras == 0 {
+		return nil, 0, gorm.ErrRecordNotFound
+	}
+
+	events := r.db.WithContext(ctx).Raw(`
+		SELECT id AS event_id, 'LPR' AS type, detected_at, photo_url
+		FROM lpr_events
+		WHERE camera_id = ? AND detected_at BETWEEN ? AND ?
+		UNION ALL
+		SELECT id AS event_id, 'VOLUME' AS type, detected_at, photo_url
+		FROM volume_events
+		WHERE camera_id = ? AND detected_at BETWEEN ? AND ?`,
+		cameraID, rng.From, rng.To, cameraID, rng.From, rng.To)
+
+	var total int64
+	if err := r.db.WithContext(ctx).Table("(?) AS camera_events", events).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]model.CameraEvent, 0, page.Limit)
+	if total == 0 {
+		return items, 0, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Table("(?) AS camera_events", events).
+		Order("detected_at DESC, event_id").
+		Limit(page.Limit).
+		Offset(page.Offset).
+		Scan(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
 func (r *AnalyticsRepository) resolveTripEvents(ctx context.Context, entryLpr, exitLpr, entryVol, exitVol *uuid.UUID) model.TripEventDetails {
 	fetch := func(table string, eventID *uuid.UUID) *model.TripEvent {
 		if eventID == nil {
@@ -882,29 +2064,118 @@ func (r *AnalyticsRepository) resolveTripEvents(ctx context.Context, entryLpr, e
 }
 
 func (r *AnalyticsRepository) ViolationSeries(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter) ([]model.SeriesPoint, error) {
-	if !r.relationExists(ctx, "mv_violation_daily") {
-		return nil, nil
+	var rows []model.SeriesPoint
+	var query *gorm.DB
+	if filter.CameraID != nil {
+		if !r.tablesAvailable(ctx, "trips", "tickets") {
+			return nil, nil
+		}
+		bucket, bucketArgs := bucketExpr("tr.entry_at", filter)
+		query = r.violationTrips(ctx, scope, filter).
+			Select(fmt.Sprintf("%s AS bucket, COUNT(*) AS count", bucket), bucketArgs...)
+	} else {
+		if !r.relationExists(ctx, "mv_violation_daily") {
+			return nil, nil
+		}
+		bucket, bucketArgs := bucketExpr("mv.bucket", filter)
+		query = r.db.WithContext(ctx).
+			Table("mv_violation_daily mv").
+			Select(fmt.Sprintf("%s AS bucket, SUM(mv.violation_count) AS count", bucket), bucketArgs...).
+			Where("mv.bucket BETWEEN ? AND ?", filter.Range.From, filter.Range.To)
+
+		query = applyContractorFilter(query, "mv.contractor_id", filter)
+		if len(filter.Statuses) > 0 {
+			query = query.Where("mv.violation_type::text IN ?", filter.Statuses)
+		}
+		query = applyMVCleaningAreaScope(query, scope)
 	}
 
-	group := buildDateTrunc(filter.GroupBy)
-	var rows []model.SeriesPoint
+	query = query.Group("bucket").Order("bucket ASC")
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return markPartialBuckets(localizeBuckets(rows, filter), filter), nil
+}
 
-	query := r.db.WithContext(ctx).
-		Table("mv_violation_daily mv").
-		Select(fmt.Sprintf("DATE_TRUNC('%s', mv.bucket) AS bucket, SUM(mv.violation_count) AS count", group)).
-		Where("mv.bucket BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
-		Group("bucket").
-		Order("bucket ASC")
+func (r *AnalyticsRepository) ViolationBreakdown(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter) ([]model.ViolationBreakdown, error) {
+	var rows []struct {
+		Type  string
+		Count int64
+	}
 
-	query = applyMVCleaningAreaScope(query, scope)
+	var query *gorm.DB
+	if filter.CameraID != nil {
+		if !r.tablesAvailable(ctx, "trips", "tickets") {
+			return nil, nil
+		}
+		query = r.violationTrips(ctx, scope, filter).
+			Select("tr.status::text AS type, COUNT(*) AS count").
+			Group("tr.status")
+	} else {
+		if !r.relationExists(ctx, "mv_violation_daily") {
+			return nil, nil
+		}
+		query = r.db.WithContext(ctx).
+			Table("mv_violation_daily mv").
+			Select("mv.violation_type AS type, SUM(mv.violation_count) AS count").
+			Where("mv.bucket BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
+			Group("mv.violation_type")
+
+		query = applyContractorFilter(query, "mv.contractor_id", filter)
+		if len(filter.Statuses) > 0 {
+			query = query.Where("mv.violation_type::text IN ?", filter.Statuses)
+		}
+		query = applyMVCleaningAreaScope(query, scope)
+	}
+
+	query = query.Order("count DESC")
 	if err := query.Scan(&rows).Error; err != nil {
 		return nil, err
 	}
-	return rows, nil
+
+	total := float64(0)
+	for _, row := range rows {
+		total += float64(row.Count)
+	}
+
+	result := make([]model.ViolationBreakdown, 0, len(rows))
+	for _, row := range rows {
+		share := 0.0
+		if total > 0 {
+			share = float64(row.Count) / total
+		}
+		result = append(result, model.ViolationBreakdown{
+			Type:  row.Type,
+			Count: row.Count,
+			Share: share,
+		})
+	}
+	return result, nil
 }
 
-func (r *AnalyticsRepository) ViolationBreakdown(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter) ([]model.ViolationBreakdown, error) {
-	if !r.relationExists(ctx, "mv_violation_daily") {
+// violationTrips selects the violating trips matching filter from the trips
+// table. mv_violation_daily keeps no camera, so camera filtered violation
+// queries read the trips instead.
+func (r *AnalyticsRepository) violationTrips(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).
+		Table("trips tr").
+		Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id").
+		Where("tr.status <> 'OK' AND tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To)
+
+	query = applyContractorFilter(query, "t.contractor_id", filter)
+	if len(filter.Statuses) > 0 {
+		query = query.Where("tr.status::text IN ?", filter.Statuses)
+	}
+	if filter.CameraID != nil {
+		query = query.Where("tr.camera_id = ?", *filter.CameraID)
+	}
+	return applyTripScope(query, scope)
+}
+
+// TripStatusBreakdown counts the trips entering in the range per status,
+// OK included. Unlike ViolationBreakdown it reads the trips table.
+func (r *AnalyticsRepository) TripStatusBreakdown(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter) ([]model.ViolationBreakdown, error) {
+	if !r.tablesAvailable(ctx, "trips", "tickets") {
 		return nil, nil
 	}
 
@@ -914,13 +2185,21 @@ func (r *AnalyticsRepository) ViolationBreakdown(ctx context.Context, scope mode
 	}
 
 	query := r.db.WithContext(ctx).
-		Table("mv_violation_daily mv").
-		Select("mv.violation_type AS type, SUM(mv.violation_count) AS count").
-		Where("mv.bucket BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
-		Group("mv.violation_type").
-		Order("count DESC")
+		Table("trips tr").
+		Select("tr.status::text AS type, COUNT(*) AS count").
+		Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id").
+		Where("tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
+		Group("tr.status").
+		Order("count DESC, type")
+
+	if filter.PolygonID != nil {
+		query = query.Where("tr.polygon_id = ?", *filter.PolygonID)
+	}
+	if filter.CameraID != nil {
+		query = query.Where("tr.camera_id = ?", *filter.CameraID)
+	}
 
-	query = applyMVCleaningAreaScope(query, scope)
+	query = applyTripScope(query, scope)
 	if err := query.Scan(&rows).Error; err != nil {
 		return nil, err
 	}
@@ -945,7 +2224,11 @@ func (r *AnalyticsRepository) ViolationBreakdown(ctx context.Context, scope mode
 	return result, nil
 }
 
-func (r *AnalyticsRepository) ViolationLeaders(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, column string, limit int) ([]model.EntityMetric, error) {
+// ViolationLeaders ranks the entities in column by violation count, or by
+// violations per trip when opts.By is LeaderByRate. The thresholds in opts
+// are checked against each entity's share of all violations in the range,
+// before the limit; Share in the result is relative to the returned rows.
+func (r *AnalyticsRepository) ViolationLeaders(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, column string, opts model.LeaderOptions) ([]model.EntityMetric, error) {
 	if !r.tablesAvailable(ctx, "trips", "tickets") {
 		return nil, nil
 	}
@@ -954,6 +2237,8 @@ func (r *AnalyticsRepository) ViolationLeaders(ctx context.Context, scope model.
 		ID    uuid.UUID
 		Name  string
 		Count int64
+		Trips int64
+		Rate  float64
 	}
 	var nameExpr string
 	switch column {
@@ -967,29 +2252,67 @@ func (r *AnalyticsRepository) ViolationLeaders(ctx context.Context, scope model.
 		nameExpr = "'Unknown'"
 	}
 
-	query := r.db.WithContext(ctx).
+	violated := "tr.status <> 'OK'"
+	var violatedArgs []interface{}
+	if len(filter.Statuses) > 0 {
+		violated += " AND tr.status::text IN ?"
+		violatedArgs = append(violatedArgs, filter.Statuses)
+	}
+	byRate := opts.By == model.LeaderByRate
+
+	trips := r.db.WithContext(ctx).
 		Table("trips tr").
-		Select(fmt.Sprintf("%s AS id, %s AS name, COUNT(*) AS count", column, nameExpr)).
+		Select(fmt.Sprintf("%s AS id, %s AS name, (%s) AS violated", column, nameExpr, violated), violatedArgs...).
 		Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id").
-		Where("tr.status <> 'OK' AND tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
-		Group(column).
-		Group(nameExpr).
-		Order("count DESC").
-		Limit(limit)
+		Where("tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To)
+	// The rate needs every trip of the entity; counting only needs the
+	// violating ones.
+	if !byRate {
+		trips = trips.Where(violated, violatedArgs...)
+	}
+	if filter.CameraID != nil {
+		trips = trips.Where("tr.camera_id = ?", *filter.CameraID)
+	}
 
 	if strings.Contains(column, "contractor") {
-		query = query.Joins("LEFT JOIN organizations org ON org.id = t.contractor_id")
+		trips = trips.Joins("LEFT JOIN organizations org ON org.id = t.contractor_id")
 	}
 	if strings.Contains(column, "driver") {
-		query = query.Joins("LEFT JOIN drivers d ON d.id = tr.driver_id")
+		trips = trips.Joins("LEFT JOIN drivers d ON d.id = tr.driver_id")
 	}
 	if strings.Contains(column, "camera") {
-		query = query.Joins("LEFT JOIN cameras c ON c.id = tr.camera_id")
+		trips = trips.Joins("LEFT JOIN cameras c ON c.id = tr.camera_id")
 	}
 
-	query = applyTripScope(query, scope)
+	trips = applyTripScope(trips, scope)
 
-	if err := query.Scan(&rows).Error; err != nil {
+	query := r.db.WithContext(ctx).
+		Table("(?) AS v", trips).
+		Select(`id, name,
+			COUNT(*) FILTER (WHERE violated) AS count,
+			COUNT(*) AS trips,
+			COUNT(*) FILTER (WHERE violated)::float8 / COUNT(*) AS rate,
+			COUNT(*) FILTER (WHERE violated)::float8 / NULLIF(SUM(COUNT(*) FILTER (WHERE violated)) OVER (), 0) AS total_share`).
+		Group("id, name")
+
+	order := "count DESC, name ASC"
+	switch {
+	case byRate:
+		order = "rate DESC, count DESC, name ASC"
+	case opts.OrderBy == model.LeaderOrderShare:
+		order = "total_share DESC, name ASC"
+	}
+	leaders := r.db.WithContext(ctx).
+		Table("(?) AS leaders", query).
+		Select("id, name, count, trips, rate").
+		Where("count > 0 AND count >= ? AND total_share >= ?", opts.MinCount, opts.MinShare).
+		Order(order).
+		Limit(opts.Limit)
+	if byRate {
+		leaders = leaders.Where("trips >= ?", opts.MinTrips)
+	}
+
+	if err := leaders.Scan(&rows).Error; err != nil {
 		return nil, err
 	}
 
@@ -1004,28 +2327,100 @@ func (r *AnalyticsRepository) ViolationLeaders(ctx context.Context, scope model.
 		if total > 0 {
 			share = float64(row.Count) / total
 		}
-		result = append(result, model.EntityMetric{
+		metric := model.EntityMetric{
 			ID:    row.ID,
 			Name:  row.Name,
 			Count: row.Count,
 			Share: share,
-		})
+		}
+		if byRate {
+			rate := row.Rate
+			metric.TripCount = row.Trips
+			metric.ViolationRate = &rate
+		}
+		result = append(result, metric)
 	}
 	return result, nil
 }
 
-func (r *AnalyticsRepository) ContractorPerformance(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, limit int) ([]model.ContractorPerformance, error) {
+// Sort keys accepted by the performance lists, mapped to their ORDER BY
+// expression. Vehicle idle hours shrink as trips grow, so they sort by the
+// negated trip count.
+var (
+	contractorSortColumns = map[string]string{
+		"trip_count":      "trip_count",
+		"avg_volume":      "avg_volume",
+		"total_volume":    "total_volume",
+		"volume_per_trip": "volume_per_trip",
+		"violation_count": "violation_count",
+		"violation_rate":  "violation_rate",
+		"active_drivers":  "drivers",
+	}
+	driverSortColumns = map[string]string{
+		"trip_count":      "trip_count",
+		"avg_volume":      "avg_volume",
+		"violation_count": "violation_count",
+		"violation_rate":  "violation_rate",
+		"avg_duration":    "avg_duration",
+	}
+	vehicleSortColumns = map[string]string{
+		"trip_count":      "trip_count",
+		"avg_fill_rate":   "avg_fill_rate",
+		"violation_count": "violation_count",
+		"violation_rate":  "violation_rate",
+		"idle_hours":      "-COUNT(*)",
+	}
+)
+
+// PerformanceSortSupported reports whether key sorts at least one of the
+// performance lists.
+func PerformanceSortSupported(key string) bool {
+	_, contractor := contractorSortColumns[key]
+	_, driver := driverSortColumns[key]
+	_, vehicle := vehicleSortColumns[key]
+	return contractor || driver || vehicle
+}
+
+// performanceOrder builds the ORDER BY clause for sort, falling back to trip
+// count descending when the key does not apply to this list.
+func performanceOrder(sort model.SortOrder, columns map[string]string) string {
+	column, ok := columns[sort.Key]
+	if !ok {
+		return "trip_count DESC"
+	}
+	direction := "ASC"
+	if sort.Desc {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("%s %s NULLS LAST, trip_count DESC", column, direction)
+}
+
+func (r *AnalyticsRepository) ContractorPerformance(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, limit int, sort model.SortOrder) ([]model.ContractorPerformance, error) {
+	return r.contractorPerformance(ctx, scope, filter, limit, performanceOrder(sort, contractorSortColumns))
+}
+
+// ContractorVolumeEfficiency ranks contractors by hauled volume per trip, so
+// contractors running half-empty trucks sink to the bottom.
+func (r *AnalyticsRepository) ContractorVolumeEfficiency(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, limit int) ([]model.ContractorPerformance, error) {
+	return r.contractorPerformance(ctx, scope, filter, limit, "volume_per_trip DESC, trip_count DESC")
+}
+
+func (r *AnalyticsRepository) contractorPerformance(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, limit int, order string) ([]model.ContractorPerformance, error) {
 	if !r.tablesAvailable(ctx, "trips", "tickets", "organizations") {
 		return nil, nil
 	}
 
 	var rows []struct {
-		ID            uuid.UUID
-		Name          string
-		TripCount     int64
-		AvgVolume     float64
-		ViolationRate float64
-		Drivers       int64
+		ID             uuid.UUID
+		Name           string
+		TripCount      int64
+		AvgVolume      float64
+		TotalVolume    float64
+		VolumePerTrip  float64
+		ViolationCount int64
+		ViolationRate  float64
+		Drivers        int64
+		ActiveDays     int64
 	}
 
 	query := r.db.WithContext(ctx).
@@ -1035,15 +2430,25 @@ func (r *AnalyticsRepository) ContractorPerformance(ctx context.Context, scope m
 			COALESCE(org.name, 'Contractor') AS name,
 			COUNT(*) AS trip_count,
 			COALESCE(AVG(tr.detected_volume_entry),0) AS avg_volume,
+			COALESCE(SUM(tr.detected_volume_entry),0) AS total_volume,
+			COALESCE(SUM(tr.detected_volume_entry),0)::float / NULLIF(COUNT(*),0) AS volume_per_trip,
+			SUM(CASE WHEN tr.status <> 'OK' THEN 1 ELSE 0 END) AS violation_count,
 			COALESCE(SUM(CASE WHEN tr.status <> 'OK' THEN 1 ELSE 0 END)::float / NULLIF(COUNT(*),0), 0) AS violation_rate,
-			COUNT(DISTINCT tr.driver_id) AS drivers`).
+			COUNT(DISTINCT tr.driver_id) AS drivers,
+			COUNT(DISTINCT (tr.entry_at AT TIME ZONE ?)::date) AS active_days`, filter.Location().String()).
 		Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id").
 		Joins("LEFT JOIN organizations org ON org.id = t.contractor_id").
 		Where("t.contractor_id IS NOT NULL AND tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
 		Group("t.contractor_id, org.name").
-		Order("trip_count DESC").
+		Order(order).
 		Limit(limit)
 
+	if filter.PolygonID != nil {
+		query = query.Where("tr.polygon_id = ?", *filter.PolygonID)
+	}
+	if filter.CameraID != nil {
+		query = query.Where("tr.camera_id = ?", *filter.CameraID)
+	}
 	query = applyTripScope(query, scope)
 
 	if err := query.Scan(&rows).Error; err != nil {
@@ -1057,26 +2462,40 @@ func (r *AnalyticsRepository) ContractorPerformance(ctx context.Context, scope m
 			ContractorName: row.Name,
 			TripCount:      row.TripCount,
 			AvgVolume:      row.AvgVolume,
-			ViolationRate:  clamp(row.ViolationRate),
+			TotalVolume:    row.TotalVolume,
+			VolumePerTrip:  clamp(row.VolumePerTrip),
+			ViolationCount: row.ViolationCount,
+			ViolationRate:  r.violationRate(row.ViolationRate, row.TripCount, "contractor", row.ID),
 			ActiveDrivers:  row.Drivers,
-			Utilization:    clamp(float64(row.TripCount) / math.Max(float64(limit), 1)),
+			Utilization:    utilization(row.ActiveDays, filter.Range),
 		})
 	}
 	return result, nil
 }
 
-func (r *AnalyticsRepository) DriverPerformance(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, limit int) ([]model.DriverPerformance, error) {
+// utilization is the share of the days of rng with at least one trip, so it
+// does not depend on the list size or on the other contractors. A partial day
+// counts as a whole one and an empty range as one day. Active days are local
+// dates, so a short range across midnight may hold more of them than it
+// spans; the share is capped at 1.
+func utilization(activeDays int64, rng model.DateRange) float64 {
+	rangeDays := math.Max(math.Ceil(rng.To.Sub(rng.From).Hours()/24), 1)
+	return math.Min(clamp(float64(activeDays)/rangeDays), 1)
+}
+
+func (r *AnalyticsRepository) DriverPerformance(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, limit int, sort model.SortOrder) ([]model.DriverPerformance, error) {
 	if !r.tablesAvailable(ctx, "trips", "tickets", "drivers") {
 		return nil, nil
 	}
 
 	var rows []struct {
-		ID            uuid.UUID
-		Name          string
-		TripCount     int64
-		AvgVolume     float64
-		ViolationRate float64
-		AvgDuration   float64
+		ID             uuid.UUID
+		Name           string
+		TripCount      int64
+		AvgVolume      float64
+		ViolationCount int64
+		ViolationRate  float64
+		AvgDuration    float64
 	}
 
 	query := r.db.WithContext(ctx).
@@ -1086,15 +2505,22 @@ func (r *AnalyticsRepository) DriverPerformance(ctx context.Context, scope model
 			COALESCE(d.full_name, 'Driver') AS name,
 			COUNT(*) AS trip_count,
 			COALESCE(AVG(tr.detected_volume_entry),0) AS avg_volume,
+			SUM(CASE WHEN tr.status <> 'OK' THEN 1 ELSE 0 END) AS violation_count,
 			COALESCE(SUM(CASE WHEN tr.status <> 'OK' THEN 1 ELSE 0 END)::float / NULLIF(COUNT(*),0), 0) AS violation_rate,
 			COALESCE(AVG(EXTRACT(EPOCH FROM (COALESCE(tr.exit_at, tr.entry_at) - tr.entry_at)) / 60),0) AS avg_duration`).
 		Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id").
 		Joins("LEFT JOIN drivers d ON d.id = tr.driver_id").
 		Where("tr.driver_id IS NOT NULL AND tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
 		Group("tr.driver_id, d.full_name").
-		Order("trip_count DESC").
+		Order(performanceOrder(sort, driverSortColumns)).
 		Limit(limit)
 
+	if filter.PolygonID != nil {
+		query = query.Where("tr.polygon_id = ?", *filter.PolygonID)
+	}
+	if filter.CameraID != nil {
+		query = query.Where("tr.camera_id = ?", *filter.CameraID)
+	}
 	query = applyTripScope(query, scope)
 
 	if err := query.Scan(&rows).Error; err != nil {
@@ -1104,20 +2530,21 @@ func (r *AnalyticsRepository) DriverPerformance(ctx context.Context, scope model
 	result := make([]model.DriverPerformance, 0, len(rows))
 	for _, row := range rows {
 		result = append(result, model.DriverPerformance{
-			DriverID:      row.ID,
-			DriverName:    row.Name,
-			TripCount:     row.TripCount,
-			AvgVolume:     row.AvgVolume,
-			ViolationRate: clamp(row.ViolationRate),
-			AvgDuration:   clamp(row.AvgDuration),
+			DriverID:       row.ID,
+			DriverName:     row.Name,
+			TripCount:      row.TripCount,
+			AvgVolume:      row.AvgVolume,
+			ViolationCount: row.ViolationCount,
+			ViolationRate:  r.violationRate(row.ViolationRate, row.TripCount, "driver", row.ID),
+			AvgDuration:    clamp(row.AvgDuration),
 		})
 	}
 	return result, nil
 }
 
-func (r *AnalyticsRepository) DriverKPIs(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter) ([]model.DriverKPI, error) {
+func (r *AnalyticsRepository) DriverKPIs(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, page model.Pagination) ([]model.DriverKPI, int64, error) {
 	if !r.tablesAvailable(ctx, "trips", "drivers", "tickets", "organizations") {
-		return nil, nil
+		return nil, 0, nil
 	}
 
 	type row struct {
@@ -1127,6 +2554,7 @@ func (r *AnalyticsRepository) DriverKPIs(ctx context.Context, scope model.Scope,
 		ContractorName *string
 		TripCount      int64
 		AvgVolume      float64
+		ViolationCount int64
 		ViolationRate  float64
 		AvgDuration    float64
 		LastTrip       *time.Time
@@ -1141,6 +2569,7 @@ func (r *AnalyticsRepository) DriverKPIs(ctx context.Context, scope model.Scope,
 			org.name AS contractor_name,
 			COUNT(*) AS trip_count,
 			COALESCE(AVG(tr.detected_volume_entry),0) AS avg_volume,
+			SUM(CASE WHEN tr.status <> 'OK' THEN 1 ELSE 0 END) AS violation_count,
 			COALESCE(SUM(CASE WHEN tr.status <> 'OK' THEN 1 ELSE 0 END)::float / NULLIF(COUNT(*),0), 0) AS violation_rate,
 			COALESCE(AVG(EXTRACT(EPOCH FROM (COALESCE(tr.exit_at, tr.entry_at) - tr.entry_at)) / 60),0) AS avg_duration,
 			MAX(tr.entry_at) AS last_trip`).
@@ -1150,17 +2579,34 @@ func (r *AnalyticsRepository) DriverKPIs(ctx context.Context, scope model.Scope,
 		Where("tr.driver_id IS NOT NULL AND tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
 		Group("tr.driver_id, d.full_name, t.contractor_id, org.name")
 
-	if filter.ContractorID != nil {
-		query = query.Where("t.contractor_id = ?", *filter.ContractorID)
-	}
+	query = applyContractorFilter(query, "t.contractor_id", filter)
 	if filter.DriverID != nil {
 		query = query.Where("tr.driver_id = ?", *filter.DriverID)
 	}
+	if filter.VehicleID != nil {
+		query = query.Where("tr.vehicle_id = ?", *filter.VehicleID)
+	}
+	if filter.PolygonID != nil {
+		query = query.Where("tr.polygon_id = ?", *filter.PolygonID)
+	}
+	if filter.CameraID != nil {
+		query = query.Where("tr.camera_id = ?", *filter.CameraID)
+	}
 
 	query = applyTripScope(query, scope)
 
+	var total int64
+	if err := r.db.WithContext(ctx).Table("(?) AS driver_kpis", query).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.
+		Order("trip_count DESC, tr.driver_id").
+		Limit(page.Limit).
+		Offset(page.Offset)
+
 	if err := query.Scan(&rows).Error; err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	result := make([]model.DriverKPI, 0, len(rows))
@@ -1172,26 +2618,29 @@ func (r *AnalyticsRepository) DriverKPIs(ctx context.Context, scope model.Scope,
 			ContractorName: row.ContractorName,
 			TripCount:      row.TripCount,
 			AvgVolume:      row.AvgVolume,
-			ViolationRate:  clamp(row.ViolationRate),
+			ViolationCount: row.ViolationCount,
+			ViolationRate:  r.violationRate(row.ViolationRate, row.TripCount, "driver", row.ID),
 			AvgDuration:    clamp(row.AvgDuration),
 			LastTripAt:     row.LastTrip,
 		})
 	}
 
-	return result, nil
+	return result, total, nil
 }
 
-func (r *AnalyticsRepository) VehiclePerformance(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, limit int) ([]model.VehiclePerformance, error) {
+func (r *AnalyticsRepository) VehiclePerformance(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter, limit int, sort model.SortOrder) ([]model.VehiclePerformance, error) {
 	if !r.tablesAvailable(ctx, "trips", "tickets", "vehicles") {
 		return nil, nil
 	}
 
 	var rows []struct {
-		ID            uuid.UUID
-		PlateNumber   string
-		TripCount     int64
-		AvgFillRate   float64
-		ViolationRate float64
+		ID                  uuid.UUID
+		PlateNumber         string
+		TripCount           int64
+		AvgFillRate         float64
+		ViolationCount      int64
+		ViolationRate       float64
+		FillRateUnavailable bool
 	}
 
 	query := r.db.WithContext(ctx).
@@ -1201,14 +2650,22 @@ func (r *AnalyticsRepository) VehiclePerformance(ctx context.Context, scope mode
 			COALESCE(v.plate_number, 'Vehicle') AS plate_number,
 			COUNT(*) AS trip_count,
 			COALESCE(AVG(CASE WHEN v.body_volume_m3 > 0 THEN tr.detected_volume_entry / v.body_volume_m3 END),0) AS avg_fill_rate,
+			COALESCE(v.body_volume_m3, 0) <= 0 AS fill_rate_unavailable,
+			SUM(CASE WHEN tr.status <> 'OK' THEN 1 ELSE 0 END) AS violation_count,
 			COALESCE(SUM(CASE WHEN tr.status <> 'OK' THEN 1 ELSE 0 END)::float / NULLIF(COUNT(*),0), 0) AS violation_rate`).
 		Joins("LEFT JOIN vehicles v ON v.id = tr.vehicle_id").
 		Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id").
 		Where("tr.vehicle_id IS NOT NULL AND tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
 		Group("tr.vehicle_id, v.plate_number, v.body_volume_m3").
-		Order("trip_count DESC").
+		Order(performanceOrder(sort, vehicleSortColumns)).
 		Limit(limit)
 
+	if filter.PolygonID != nil {
+		query = query.Where("tr.polygon_id = ?", *filter.PolygonID)
+	}
+	if filter.CameraID != nil {
+		query = query.Where("tr.camera_id = ?", *filter.CameraID)
+	}
 	query = applyTripScope(query, scope)
 
 	if err := query.Scan(&rows).Error; err != nil {
@@ -1220,12 +2677,14 @@ func (r *AnalyticsRepository) VehiclePerformance(ctx context.Context, scope mode
 	for _, row := range rows {
 		idle := math.Max(rangeHours-(float64(row.TripCount)*1.5), 0)
 		result = append(result, model.VehiclePerformance{
-			VehicleID:     row.ID,
-			PlateNumber:   row.PlateNumber,
-			TripCount:     row.TripCount,
-			AvgFillRate:   clamp(row.AvgFillRate),
-			ViolationRate: clamp(row.ViolationRate),
-			IdleHours:     idle,
+			VehicleID:           row.ID,
+			PlateNumber:         row.PlateNumber,
+			TripCount:           row.TripCount,
+			AvgFillRate:         clamp(row.AvgFillRate),
+			ViolationCount:      row.ViolationCount,
+			ViolationRate:       r.violationRate(row.ViolationRate, row.TripCount, "vehicle", row.ID),
+			IdleHours:           idle,
+			FillRateUnavailable: row.FillRateUnavailable,
 		})
 	}
 	return result, nil
@@ -1237,14 +2696,16 @@ func (r *AnalyticsRepository) VehicleKPIs(ctx context.Context, scope model.Scope
 	}
 
 	type row struct {
-		ID             uuid.UUID
-		PlateNumber    string
-		ContractorID   *uuid.UUID
-		ContractorName *string
-		TripCount      int64
-		AvgFillRate    float64
-		ViolationRate  float64
-		LastTrip       *time.Time
+		ID                  uuid.UUID
+		PlateNumber         string
+		ContractorID        *uuid.UUID
+		ContractorName      *string
+		TripCount           int64
+		AvgFillRate         float64
+		ViolationCount      int64
+		ViolationRate       float64
+		LastTrip            *time.Time
+		FillRateUnavailable bool
 	}
 	var rows []row
 
@@ -1256,6 +2717,8 @@ func (r *AnalyticsRepository) VehicleKPIs(ctx context.Context, scope model.Scope
 			org.name AS contractor_name,
 			COUNT(*) AS trip_count,
 			COALESCE(AVG(CASE WHEN v.body_volume_m3 > 0 THEN tr.detected_volume_entry / v.body_volume_m3 END),0) AS avg_fill_rate,
+			COALESCE(v.body_volume_m3, 0) <= 0 AS fill_rate_unavailable,
+			SUM(CASE WHEN tr.status <> 'OK' THEN 1 ELSE 0 END) AS violation_count,
 			COALESCE(SUM(CASE WHEN tr.status <> 'OK' THEN 1 ELSE 0 END)::float / NULLIF(COUNT(*),0), 0) AS violation_rate,
 			MAX(tr.entry_at) AS last_trip`).
 		Joins("LEFT JOIN vehicles v ON v.id = tr.vehicle_id").
@@ -1264,8 +2727,15 @@ func (r *AnalyticsRepository) VehicleKPIs(ctx context.Context, scope model.Scope
 		Where("tr.vehicle_id IS NOT NULL AND tr.entry_at BETWEEN ? AND ?", filter.Range.From, filter.Range.To).
 		Group("tr.vehicle_id, v.plate_number, t.contractor_id, org.name, v.body_volume_m3")
 
-	if filter.ContractorID != nil {
-		query = query.Where("t.contractor_id = ?", *filter.ContractorID)
+	query = applyContractorFilter(query, "t.contractor_id", filter)
+	if filter.VehicleID != nil {
+		query = query.Where("tr.vehicle_id = ?", *filter.VehicleID)
+	}
+	if filter.PolygonID != nil {
+		query = query.Where("tr.polygon_id = ?", *filter.PolygonID)
+	}
+	if filter.CameraID != nil {
+		query = query.Where("tr.camera_id = ?", *filter.CameraID)
 	}
 
 	query = applyTripScope(query, scope)
@@ -1287,15 +2757,17 @@ func (r *AnalyticsRepository) VehicleKPIs(ctx context.Context, scope model.Scope
 			idle = rangeHours
 		}
 		result = append(result, model.VehicleKPI{
-			VehicleID:      row.ID,
-			PlateNumber:    row.PlateNumber,
-			ContractorID:   row.ContractorID,
-			ContractorName: row.ContractorName,
-			TripCount:      row.TripCount,
-			AvgFillRate:    clamp(row.AvgFillRate),
-			ViolationRate:  clamp(row.ViolationRate),
-			IdleHours:      clamp(idle),
-			LastTripAt:     row.LastTrip,
+			VehicleID:           row.ID,
+			PlateNumber:         row.PlateNumber,
+			ContractorID:        row.ContractorID,
+			ContractorName:      row.ContractorName,
+			TripCount:           row.TripCount,
+			AvgFillRate:         clamp(row.AvgFillRate),
+			ViolationCount:      row.ViolationCount,
+			ViolationRate:       r.violationRate(row.ViolationRate, row.TripCount, "vehicle", row.ID),
+			IdleHours:           clamp(idle),
+			LastTripAt:          row.LastTrip,
+			FillRateUnavailable: row.FillRateUnavailable,
 		})
 	}
 
@@ -1307,7 +2779,7 @@ func (r *AnalyticsRepository) TechnicalAnalytics(ctx context.Context, scope mode
 		return model.TechnicalAnalytics{}, nil
 	}
 
-	cameras, err := r.CameraLoad(ctx, scope, rng)
+	cameras, err := r.CameraLoad(ctx, scope, rng, nil)
 	if err != nil {
 		return model.TechnicalAnalytics{}, err
 	}
@@ -1403,27 +2875,195 @@ func (r *AnalyticsRepository) TechnicalAnalytics(ctx context.Context, scope mode
 	}, nil
 }
 
+// duplicateTripMatch matches, as dup, an earlier trip (by entry_at, then id)
+// of the same driver and vehicle as tr that entered at most the bound number
+// of seconds before it. Such a tr is most likely a double insert by ingestion.
+const duplicateTripMatch = `dup.driver_id = tr.driver_id
+	AND dup.vehicle_id = tr.vehicle_id
+	AND (dup.entry_at, dup.id) < (tr.entry_at, tr.id)
+	AND dup.entry_at >= tr.entry_at - ? * INTERVAL '1 second'`
+
+// DuplicateTrips counts the likely duplicate trips entering within rng and
+// returns the newest sampleLimit of them with the trip each one repeats.
+func (r *AnalyticsRepository) DuplicateTrips(ctx context.Context, scope model.Scope, rng model.DateRange, window time.Duration, sampleLimit int) (model.TripQuality, error) {
+	result := model.TripQuality{Duplicates: []model.DuplicateTrip{}}
+	if !r.tablesAvailable(ctx, "trips", "tickets") {
+		return result, nil
+	}
+
+	base := func() *gorm.DB {
+		query := r.db.WithContext(ctx).
+			Table("trips tr").
+			Joins("LEFT JOIN tickets t ON t.id = tr.ticket_id").
+			Where("tr.entry_at BETWEEN ? AND ?", rng.From, rng.To)
+		return applyTripScope(query, scope)
+	}
+
+	var counts struct {
+		TotalTrips     int64
+		DuplicateTrips int64
+	}
+	seconds := window.Seconds()
+	err := base().
+		Select(fmt.Sprintf(`COUNT(*) AS total_trips,
+			COALESCE(SUM(CASE WHEN EXISTS (SELECT 1 FROM trips dup WHERE %s) THEN 1 ELSE 0 END), 0) AS duplicate_trips`, duplicateTripMatch), seconds).
+		Scan(&counts).Error
+	if err != nil {
+		return result, err
+	}
+	result.TotalTrips = counts.TotalTrips
+	result.DuplicateTrips = counts.DuplicateTrips
+	if result.DuplicateTrips == 0 || sampleLimit <= 0 {
+		return result, nil
+	}
+
+	err = base().
+		Select(fmt.Sprintf(`tr.id AS trip_id,
+			(SELECT dup.id FROM trips dup WHERE %s ORDER BY dup.entry_at DESC, dup.id DESC LIMIT 1) AS duplicate_of,
+			tr.driver_id,
+			tr.vehicle_id,
+			tr.entry_at`, duplicateTripMatch), seconds).
+		Where(fmt.Sprintf("EXISTS (SELECT 1 FROM trips dup WHERE %s)", duplicateTripMatch), seconds).
+		Order("tr.entry_at DESC, tr.id").
+		Limit(sampleLimit).
+		Scan(&result.Duplicates).Error
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// CameraCoverage counts cameras per polygon, polygons without cameras first.
+func (r *AnalyticsRepository) CameraCoverage(ctx context.Context) ([]model.PolygonCoverage, error) {
+	if !r.tablesAvailable(ctx, "polygons", "cameras") {
+		return []model.PolygonCoverage{}, nil
+	}
+
+	type row struct {
+		ID          uuid.UUID
+		Name        string
+		CameraCount int64
+	}
+	var rows []row
+
+	err := r.db.WithContext(ctx).
+		Table("polygons p").
+		Select("p.id, COALESCE(p.name, 'Polygon') AS name, COUNT(c.id) AS camera_count").
+		Joins("LEFT JOIN cameras c ON c.polygon_id = p.id").
+		Group("p.id, p.name").
+		Order("camera_count ASC, p.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	coverage := make([]model.PolygonCoverage, 0, len(rows))
+	for _, row := range rows {
+		coverage = append(coverage, model.PolygonCoverage{
+			PolygonID:   row.ID,
+			PolygonName: row.Name,
+			CameraCount: row.CameraCount,
+			Uncovered:   row.CameraCount == 0,
+		})
+	}
+	return coverage, nil
+}
+
 func deriveContractStatus(start, end time.Time, now time.Time) string {
 	if now.Before(start) {
-		return "PLANNED"
+		return model.ContractStatusPlanned
 	}
 	if now.After(end) {
-		return "EXPIRED"
+		return model.ContractStatusExpired
+	}
+	return model.ContractStatusActive
+}
+
+type contractProgressInput struct {
+	start, end, now            time.Time
+	totalVolume, minimalVolume float64
+	totalCost, budgetTotal     float64
+}
+
+// deriveContractResult returns SUCCESS/FAIL for expired contracts and, for
+// active ones, OVER_BUDGET once the cost exceeds the budget, else ON_TRACK or
+// BEHIND depending on whether volume progress keeps up with the elapsed share
+// of the contract period. Anything without a minimal volume is NONE.
+func deriveContractResult(status string, in contractProgressInput) string {
+	switch status {
+	case model.ContractStatusExpired:
+		if in.minimalVolume == 0 {
+			return model.ContractResultNone
+		}
+		if in.totalVolume >= in.minimalVolume {
+			return model.ContractResultSuccess
+		}
+		return model.ContractResultFail
+	case model.ContractStatusActive:
+		if in.budgetTotal > 0 && in.totalCost > in.budgetTotal {
+			return model.ContractResultOverBudget
+		}
+		if in.minimalVolume == 0 {
+			return model.ContractResultNone
+		}
+		elapsed := 1.0
+		if period := in.end.Sub(in.start); period > 0 {
+			elapsed = float64(in.now.Sub(in.start)) / float64(period)
+		}
+		if in.totalVolume/in.minimalVolume >= elapsed {
+			return model.ContractResultOnTrack
+		}
+		return model.ContractResultBehind
+	default:
+		return model.ContractResultNone
 	}
-	return "ACTIVE"
 }
 
-func deriveContractResult(status string, totalVolume, minimalVolume float64) string {
-	if status != "EXPIRED" {
-		return "NONE"
+// elapsedContractDays counts the started days of a contract up to now or
+// its end, whichever comes first; 0 before the start.
+func elapsedContractDays(start, end, now time.Time) float64 {
+	if end.Before(now) {
+		now = end
+	}
+	elapsed := now.Sub(start)
+	if elapsed < 0 {
+		return 0
 	}
-	if minimalVolume == 0 {
-		return "NONE"
+	return math.Max(math.Ceil(elapsed.Hours()/24), 1)
+}
+
+// deriveProjectedCost extrapolates the cost so far to the end of the
+// contract at the pace of the elapsed share of its period. Planned
+// contracts, and active ones with no time elapsed, have no projection;
+// expired ones project their actual cost.
+func deriveProjectedCost(status string, in contractProgressInput) *float64 {
+	switch status {
+	case model.ContractStatusExpired:
+		cost := in.totalCost
+		return &cost
+	case model.ContractStatusActive:
+		period := in.end.Sub(in.start)
+		elapsed := in.now.Sub(in.start)
+		if period <= 0 || elapsed <= 0 {
+			return nil
+		}
+		cost := in.totalCost * float64(period) / float64(elapsed)
+		return &cost
+	default:
+		return nil
 	}
-	if totalVolume >= minimalVolume {
-		return "SUCCESS"
+}
+
+// bboxSRID is the SRID of the stored area and polygon geometries.
+const bboxSRID = 4326
+
+// applyBBox keeps rows whose geometry column intersects bbox; a nil bbox
+// leaves the query unchanged.
+func applyBBox(query *gorm.DB, column string, bbox *model.BBox) *gorm.DB {
+	if bbox == nil {
+		return query
 	}
-	return "FAIL"
+	return query.Where(column+" && ST_MakeEnvelope(?, ?, ?, ?, ?)", bbox.MinLon, bbox.MinLat, bbox.MaxLon, bbox.MaxLat, bboxSRID)
 }
 
 func applyTripScope(query *gorm.DB, scope model.Scope) *gorm.DB {
@@ -1431,16 +3071,21 @@ func applyTripScope(query *gorm.DB, scope model.Scope) *gorm.DB {
 	case model.ScopeCity:
 		return query
 	case model.ScopeKgu:
-		if scope.OrgID != nil {
+		if orgs := scope.Organizations(); len(orgs) > 0 {
 			if len(scope.ContractorIDs) > 0 {
-				return query.Where("(t.created_by_org_id = ? OR t.contractor_id IN ?)", *scope.OrgID, scope.ContractorIDs)
+				return query.Where("(t.created_by_org_id IN ? OR t.contractor_id IN ?)", orgs, scope.ContractorIDs)
 			}
-			return query.Where("t.created_by_org_id = ?", *scope.OrgID)
+			return query.Where("t.created_by_org_id IN ?", orgs)
 		}
 	case model.ScopeContractor:
-		if scope.OrgID != nil {
-			return query.Where("t.contractor_id = ?", *scope.OrgID)
+		if orgs := scope.Organizations(); len(orgs) > 0 {
+			return query.Where("t.contractor_id IN ?", orgs)
+		}
+	case model.ScopeDriver:
+		if scope.DriverID != nil {
+			return query.Where("tr.driver_id = ?", *scope.DriverID)
 		}
+		return query.Where("1 = 0")
 	case model.ScopeTechnical:
 		return query.Where("1 = 0")
 	}
@@ -1452,14 +3097,14 @@ func applyTicketScope(query *gorm.DB, scope model.Scope) *gorm.DB {
 	case model.ScopeCity:
 		return query
 	case model.ScopeKgu:
-		if scope.OrgID != nil {
-			return query.Where("t.created_by_org_id = ?", *scope.OrgID)
+		if orgs := scope.Organizations(); len(orgs) > 0 {
+			return query.Where("t.created_by_org_id IN ?", orgs)
 		}
 	case model.ScopeContractor:
-		if scope.OrgID != nil {
-			return query.Where("t.contractor_id = ?", *scope.OrgID)
+		if orgs := scope.Organizations(); len(orgs) > 0 {
+			return query.Where("t.contractor_id IN ?", orgs)
 		}
-	case model.ScopeTechnical:
+	case model.ScopeTechnical, model.ScopeDriver:
 		return query.Where("1 = 0")
 	}
 	return query
@@ -1470,12 +3115,12 @@ func applyContractScope(query *gorm.DB, scope model.Scope) *gorm.DB {
 	case model.ScopeCity:
 		return query
 	case model.ScopeKgu:
-		if scope.OrgID != nil {
-			return query.Where("c.created_by_org = ?", *scope.OrgID)
+		if orgs := scope.Organizations(); len(orgs) > 0 {
+			return query.Where("c.created_by_org IN ?", orgs)
 		}
 	case model.ScopeContractor:
-		if scope.OrgID != nil {
-			return query.Where("c.contractor_id = ?", *scope.OrgID)
+		if orgs := scope.Organizations(); len(orgs) > 0 {
+			return query.Where("c.contractor_id IN ?", orgs)
 		}
 	default:
 		return query.Where("1 = 0")
@@ -1483,6 +3128,21 @@ func applyContractScope(query *gorm.DB, scope model.Scope) *gorm.DB {
 	return query
 }
 
+// violationRate clamps a SQL-computed violation rate. Every group reaching this
+// point has at least one trip, so a NaN/Inf here means the rate expression
+// regressed rather than an empty group; log it instead of silently zeroing.
+func (r *AnalyticsRepository) violationRate(value float64, tripCount int64, entity string, id uuid.UUID) float64 {
+	if tripCount > 0 && (math.IsNaN(value) || math.IsInf(value, 0)) {
+		r.log.Warn().
+			Str("entity", entity).
+			Str("entity_id", id.String()).
+			Int64("trip_count", tripCount).
+			Float64("violation_rate", value).
+			Msg("violation rate is not finite for a group with trips")
+	}
+	return clamp(value)
+}
+
 func clamp(value float64) float64 {
 	if math.IsNaN(value) || math.IsInf(value, 0) {
 		return 0
@@ -1506,6 +3166,180 @@ func (r *AnalyticsRepository) relationExists(ctx context.Context, name string) b
 	return exists
 }
 
+func (r *AnalyticsRepository) columnExists(ctx context.Context, table, column string) bool {
+	var exists bool
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT EXISTS (
+			SELECT 1
+			FROM information_schema.columns
+			WHERE table_schema = 'public' AND table_name = ? AND column_name = ?
+		)`, table, column).
+		Scan(&exists).Error
+	if err != nil {
+		return false
+	}
+	return exists
+}
+
+// spatialAvailable reports whether table has a geometry column that can be
+// filtered with PostGIS operators.
+func (r *AnalyticsRepository) spatialAvailable(ctx context.Context, table string) bool {
+	return r.functionExists(ctx, "st_makeenvelope") && r.columnExists(ctx, table, "geometry")
+}
+
+// functionExists reports whether a function of that name is installed in any
+// schema, e.g. to detect extensions such as PostGIS.
+func (r *AnalyticsRepository) functionExists(ctx context.Context, name string) bool {
+	var exists bool
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_proc WHERE proname = ?)`, name).
+		Scan(&exists).Error
+	if err != nil {
+		return false
+	}
+	return exists
+}
+
+// Ping checks that the database answers.
+func (r *AnalyticsRepository) Ping(ctx context.Context) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// MissingViews lists the materialized views that do not exist (yet).
+func (r *AnalyticsRepository) MissingViews(ctx context.Context) []string {
+	missing := make([]string, 0)
+	for _, name := range materializedViews {
+		if !r.relationExists(ctx, name) {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+func (r *AnalyticsRepository) MaterializedViews() []string {
+	return append([]string(nil), materializedViews...)
+}
+
+func (r *AnalyticsRepository) RefreshMaterializedView(ctx context.Context, name string) error {
+	known := false
+	for _, view := range materializedViews {
+		if view == name {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("unknown materialized view %q", name)
+	}
+	if !r.relationExists(ctx, name) {
+		return ErrViewMissing
+	}
+	return r.db.WithContext(ctx).Exec(fmt.Sprintf("REFRESH MATERIALIZED VIEW CONCURRENTLY %s", name)).Error
+}
+
+// consistencySpec describes how to recompute a materialized view's totals
+// from trips: the view's count/volume columns and the join and filter of
+// its definition.
+type consistencySpec struct {
+	countColumn  string
+	volumeColumn string
+	join         string
+	where        string
+}
+
+var consistencySpecs = map[string]consistencySpec{
+	"mv_trip_daily": {
+		countColumn:  "total_trips",
+		volumeColumn: "total_volume_m3",
+		join:         "LEFT JOIN tickets t ON t.id = tr.ticket_id",
+	},
+	"mv_violation_daily": {
+		countColumn: "violation_count",
+		join:        "LEFT JOIN tickets t ON t.id = tr.ticket_id",
+		where:       "tr.status <> 'OK'",
+	},
+	"mv_contract_daily": {
+		countColumn:  "total_trips",
+		volumeColumn: "total_volume_m3",
+		join:         "JOIN tickets t ON t.id = tr.ticket_id",
+	},
+	"mv_cleaning_area_daily": {
+		countColumn:  "total_trips",
+		volumeColumn: "total_volume_m3",
+		join:         "JOIN tickets t ON t.id = tr.ticket_id",
+	},
+}
+
+// ViewConsistency compares the totals of a materialized view with a live
+// aggregate over trips, both starting at the day of since. Callers must keep
+// since recent: the live side scans trips.
+func (r *AnalyticsRepository) ViewConsistency(ctx context.Context, view string, since time.Time) (model.ViewConsistency, error) {
+	result := model.ViewConsistency{View: view, Status: "SKIPPED"}
+	spec, ok := consistencySpecs[view]
+	if !ok {
+		return result, fmt.Errorf("unknown materialized view %q", view)
+	}
+	if !r.relationExists(ctx, view) || !r.tablesAvailable(ctx, "trips", "tickets") {
+		return result, nil
+	}
+
+	type totals struct {
+		Trips  int64
+		Volume *float64
+	}
+
+	volumeSelect := "NULL::float8"
+	liveVolumeSelect := "NULL::float8"
+	if spec.volumeColumn != "" {
+		volumeSelect = fmt.Sprintf("COALESCE(SUM(%s), 0)", spec.volumeColumn)
+		liveVolumeSelect = "COALESCE(SUM(tr.detected_volume_entry), 0)"
+	}
+
+	var mv totals
+	err := r.db.WithContext(ctx).
+		Table(view).
+		Select(fmt.Sprintf("COALESCE(SUM(%s), 0) AS trips, %s AS volume", spec.countColumn, volumeSelect)).
+		Where("bucket >= DATE_TRUNC('day', ?::timestamptz)", since).
+		Scan(&mv).Error
+	if err != nil {
+		return result, err
+	}
+
+	// Buckets are day boundaries, so entry_at >= the truncated day matches
+	// DATE_TRUNC('day', entry_at) >= it while still using the index.
+	var live totals
+	query := r.db.WithContext(ctx).
+		Table("trips tr").
+		Select(fmt.Sprintf("COUNT(*) AS trips, %s AS volume", liveVolumeSelect)).
+		Joins(spec.join).
+		Where("tr.entry_at >= DATE_TRUNC('day', ?::timestamptz)", since)
+	if spec.where != "" {
+		query = query.Where(spec.where)
+	}
+	if err := query.Scan(&live).Error; err != nil {
+		return result, err
+	}
+
+	result.MVTrips = mv.Trips
+	result.LiveTrips = live.Trips
+	result.TripDelta = live.Trips - mv.Trips
+	result.MVVolume = mv.Volume
+	result.LiveVolume = live.Volume
+	if mv.Volume != nil && live.Volume != nil {
+		delta := *live.Volume - *mv.Volume
+		result.VolumeDelta = &delta
+	}
+	result.Status = "OK"
+	if result.TripDelta != 0 || (result.VolumeDelta != nil && math.Abs(*result.VolumeDelta) > 1e-6) {
+		result.Status = "DRIFT"
+	}
+	return result, nil
+}
+
 func (r *AnalyticsRepository) tablesAvailable(ctx context.Context, names ...string) bool {
 	for _, name := range names {
 		if !r.relationExists(ctx, name) {
@@ -1515,20 +3349,64 @@ func (r *AnalyticsRepository) tablesAvailable(ctx context.Context, names ...stri
 	return true
 }
 
+// applyContractorFilter restricts column to filter.ContractorID and, when
+// set, to filter.ContractorIDs. A non-nil empty ContractorIDs matches
+// nothing (e.g. a contractor_name without matches).
+func applyContractorFilter(query *gorm.DB, column string, filter model.AnalyticsFilter) *gorm.DB {
+	if filter.ContractorID != nil {
+		query = query.Where(column+" = ?", *filter.ContractorID)
+	}
+	if filter.ContractorIDs != nil {
+		if len(filter.ContractorIDs) == 0 {
+			return query.Where("1 = 0")
+		}
+		query = query.Where(column+" IN ?", filter.ContractorIDs)
+	}
+	return query
+}
+
+// FindContractors lists contractors visible in scope whose name contains
+// fragment (case-insensitive), ordered by name.
+func (r *AnalyticsRepository) FindContractors(ctx context.Context, scope model.Scope, fragment string, limit int) ([]model.ContractorMatch, error) {
+	matches := make([]model.ContractorMatch, 0)
+	if scope.Type == model.ScopeTechnical || !r.tablesAvailable(ctx, "organizations") {
+		return matches, nil
+	}
+
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(fragment)
+	query := r.db.WithContext(ctx).
+		Table("organizations org").
+		Select("org.id, org.name").
+		Where("org.type = ? AND org.name ILIKE ?", orgTypeContractor, "%"+escaped+"%").
+		Order("org.name ASC").
+		Limit(limit)
+	if scope.Type != model.ScopeCity {
+		if len(scope.ContractorIDs) == 0 {
+			return matches, nil
+		}
+		query = query.Where("org.id IN ?", scope.ContractorIDs)
+	}
+
+	if err := query.Scan(&matches).Error; err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
 func applyMVTripScope(query *gorm.DB, scope model.Scope) *gorm.DB {
 	switch scope.Type {
 	case model.ScopeCity:
 		return query
 	case model.ScopeKgu:
-		if scope.OrgID != nil {
+		if orgs := scope.Organizations(); len(orgs) > 0 {
 			if len(scope.ContractorIDs) > 0 {
-				return query.Where("(mv.created_by_org_id = ? OR mv.contractor_id IN ?)", *scope.OrgID, scope.ContractorIDs)
+				return query.Where("(mv.created_by_org_id IN ? OR mv.contractor_id IN ?)", orgs, scope.ContractorIDs)
 			}
-			return query.Where("mv.created_by_org_id = ?", *scope.OrgID)
+			return query.Where("mv.created_by_org_id IN ?", orgs)
 		}
 	case model.ScopeContractor:
-		if scope.OrgID != nil {
-			return query.Where("mv.contractor_id = ?", *scope.OrgID)
+		if orgs := scope.Organizations(); len(orgs) > 0 {
+			return query.Where("mv.contractor_id IN ?", orgs)
 		}
 	default:
 		return query.Where("1 = 0")
@@ -1541,15 +3419,15 @@ func applyMVCleaningAreaScope(query *gorm.DB, scope model.Scope) *gorm.DB {
 	case model.ScopeCity:
 		return query
 	case model.ScopeKgu:
-		if scope.OrgID != nil {
+		if orgs := scope.Organizations(); len(orgs) > 0 {
 			if len(scope.ContractorIDs) > 0 {
-				return query.Where("(mv.created_by_org_id = ? OR mv.contractor_id IN ?)", *scope.OrgID, scope.ContractorIDs)
+				return query.Where("(mv.created_by_org_id IN ? OR mv.contractor_id IN ?)", orgs, scope.ContractorIDs)
 			}
-			return query.Where("mv.created_by_org_id = ?", *scope.OrgID)
+			return query.Where("mv.created_by_org_id IN ?", orgs)
 		}
 	case model.ScopeContractor:
-		if scope.OrgID != nil {
-			return query.Where("mv.contractor_id = ?", *scope.OrgID)
+		if orgs := scope.Organizations(); len(orgs) > 0 {
+			return query.Where("mv.contractor_id IN ?", orgs)
 		}
 	default:
 		return query.Where("1 = 0")
@@ -1559,6 +3437,8 @@ func applyMVCleaningAreaScope(query *gorm.DB, scope model.Scope) *gorm.DB {
 
 func normalizeGroupBy(groupBy model.GroupBy) string {
 	switch groupBy {
+	case model.GroupByHour:
+		return "hour"
 	case model.GroupByWeek:
 		return "week"
 	case model.GroupByMonth:
@@ -1570,6 +3450,8 @@ func normalizeGroupBy(groupBy model.GroupBy) string {
 
 func buildDateTrunc(groupBy model.GroupBy) string {
 	switch groupBy {
+	case model.GroupByHour:
+		return "hour"
 	case model.GroupByWeek:
 		return "week"
 	case model.GroupByMonth:
@@ -1579,6 +3461,74 @@ func buildDateTrunc(groupBy model.GroupBy) string {
 	}
 }
 
+// bucketExpr returns the bucket expression over a timestamptz column together
+// with its bind args. Buckets are cut in the filter's time zone (local
+// midnight, local week start) and converted back to timestamptz. Interval
+// buckets are anchored at local midnight so 6h buckets line up with shifts.
+func bucketExpr(column string, filter model.AnalyticsFilter) (string, []interface{}) {
+	tz := filter.Location().String()
+	if filter.Interval > 0 {
+		expr := fmt.Sprintf("date_bin(INTERVAL '%d seconds', %s AT TIME ZONE ?, TIMESTAMP '2000-01-01 00:00:00') AT TIME ZONE ?", int64(filter.Interval/time.Second), column)
+		return expr, []interface{}{tz, tz}
+	}
+	expr := fmt.Sprintf("DATE_TRUNC('%s', %s AT TIME ZONE ?) AT TIME ZONE ?", buildDateTrunc(filter.GroupBy), column)
+	return expr, []interface{}{tz, tz}
+}
+
+// localizeBuckets reports bucket timestamps in the filter's time zone.
+func localizeBuckets(points []model.SeriesPoint, filter model.AnalyticsFilter) []model.SeriesPoint {
+	loc := filter.Location()
+	for i := range points {
+		points[i].Bucket = points[i].Bucket.In(loc)
+	}
+	return points
+}
+
+// markPartialBuckets flags the buckets that start before the range or end
+// after it. Buckets are cut by the SQL bucket expression regardless of the
+// range, so the first and last ones may cover only part of their span.
+func markPartialBuckets(points []model.SeriesPoint, filter model.AnalyticsFilter) []model.SeriesPoint {
+	if filter.Range.From.IsZero() || filter.Range.To.IsZero() {
+		return points
+	}
+	for i := range points {
+		start := points[i].Bucket
+		points[i].Partial = start.Before(filter.Range.From) || bucketEnd(start, filter).After(filter.Range.To.Add(time.Nanosecond))
+	}
+	return points
+}
+
+// bucketEnd returns the exclusive end of the bucket starting at start, in
+// the bucket's own time zone so months and DST days keep their length.
+func bucketEnd(start time.Time, filter model.AnalyticsFilter) time.Time {
+	if filter.Interval > 0 {
+		return start.Add(filter.Interval)
+	}
+	switch filter.GroupBy {
+	case model.GroupByHour:
+		return start.Add(time.Hour)
+	case model.GroupByWeek:
+		return start.AddDate(0, 0, 7)
+	case model.GroupByMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+func entitySeriesColumn(entity model.GroupByEntity) string {
+	switch entity {
+	case model.GroupByEntityContractor:
+		return "mv.contractor_id"
+	case model.GroupByEntityDriver:
+		return "mv.driver_id"
+	case model.GroupByEntityArea:
+		return "mv.cleaning_area_id"
+	default:
+		return ""
+	}
+}
+
 func placeholderList(ids []uuid.UUID) string {
 	builder := strings.Builder{}
 	for i := range ids {
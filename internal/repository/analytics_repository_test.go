@@ -0,0 +1,487 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"analytics-service/internal/model"
+)
+
+func testFilter() model.AnalyticsFilter {
+	to := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	return model.AnalyticsFilter{Range: model.DateRange{From: to.AddDate(0, 0, -30), To: to}, GroupBy: model.GroupByDay}
+}
+
+func TestContractorDetailQueriesAreNarrowedToTheContractor(t *testing.T) {
+	contractorA, contractorB := uuid.New(), uuid.New()
+	scope := model.Scope{Type: model.ScopeKgu, OrgID: ptr(uuid.New()), ContractorIDs: []uuid.UUID{contractorA, contractorB}}
+	filter := testFilter()
+	filter.ContractorID = &contractorA
+
+	tests := []struct {
+		name     string
+		run      func(context.Context, *AnalyticsRepository) error
+		fragment string
+		column   string
+	}{
+		{"violation breakdown", func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.ViolationBreakdown(ctx, scope, filter)
+			return err
+		}, "FROM mv_violation_daily", "mv.contractor_id = "},
+		{"violation series", func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.ViolationSeries(ctx, scope, filter)
+			return err
+		}, "FROM mv_violation_daily", "mv.contractor_id = "},
+		{"top drivers", func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TopDrivers(ctx, scope, filter, 5, model.RankByCount)
+			return err
+		}, "FROM trips tr", "t.contractor_id = "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, rec := newRecordingRepo(t)
+			if err := tt.run(context.Background(), repo); err != nil {
+				t.Fatal(err)
+			}
+			queries := rec.find(tt.fragment)
+			if len(queries) != 1 {
+				t.Fatalf("got %d queries on %q, want 1", len(queries), tt.fragment)
+			}
+			got, ok := queries[0].boundTo(tt.column)
+			if !ok || got != contractorA.String() {
+				t.Errorf("query not narrowed to contractor %s: %s", contractorA, queries[0].SQL)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestTripLeadersHonorContractorIDs(t *testing.T) {
+	chosen := []uuid.UUID{uuid.New(), uuid.New()}
+	filter := testFilter()
+	filter.ContractorIDs = chosen
+
+	leaders := map[string]func(context.Context, *AnalyticsRepository) error{
+		"top drivers": func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TopDrivers(ctx, model.Scope{Type: model.ScopeCity}, filter, 5, model.RankByCount)
+			return err
+		},
+		"top contractors": func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TopContractors(ctx, model.Scope{Type: model.ScopeCity}, filter, 5, model.RankByCount)
+			return err
+		},
+	}
+	for name, run := range leaders {
+		t.Run(name, func(t *testing.T) {
+			repo, rec := newRecordingRepo(t)
+			if err := run(context.Background(), repo); err != nil {
+				t.Fatal(err)
+			}
+			queries := rec.find("FROM trips tr")
+			if len(queries) != 1 || !strings.Contains(queries[0].SQL, "t.contractor_id IN ($3,$4)") {
+				t.Fatalf("leaders not narrowed to the chosen contractors: %v", queries)
+			}
+		})
+	}
+}
+
+func TestTripQueriesHonorCameraID(t *testing.T) {
+	camera := uuid.New()
+	scope := model.Scope{Type: model.ScopeCity}
+	filter := testFilter()
+	filter.CameraID = &camera
+
+	queries := map[string]func(context.Context, *AnalyticsRepository) error{
+		"series": func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TripSeries(ctx, scope, filter)
+			return err
+		},
+		"volume series": func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TripVolumeSeries(ctx, scope, filter)
+			return err
+		},
+		"top drivers": func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TopDrivers(ctx, scope, filter, 5, model.RankByCount)
+			return err
+		},
+		"top contractors": func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TopContractors(ctx, scope, filter, 5, model.RankByCount)
+			return err
+		},
+		"duration stats": func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TripDurationStats(ctx, scope, filter)
+			return err
+		},
+		"volume stats": func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TripVolumeStats(ctx, scope, filter)
+			return err
+		},
+		"status breakdown": func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TripStatusBreakdown(ctx, scope, filter)
+			return err
+		},
+		"violation series": func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.ViolationSeries(ctx, scope, filter)
+			return err
+		},
+		"violation breakdown": func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.ViolationBreakdown(ctx, scope, filter)
+			return err
+		},
+	}
+	for name, run := range queries {
+		t.Run(name, func(t *testing.T) {
+			repo, rec := newRecordingRepo(t)
+			if err := run(context.Background(), repo); err != nil {
+				t.Fatal(err)
+			}
+			trips := rec.find("FROM trips tr")
+			if len(trips) != 1 {
+				t.Fatalf("got %d queries on trips, want 1", len(trips))
+			}
+			if got, ok := trips[0].boundTo("tr.camera_id = "); !ok || got != camera.String() {
+				t.Errorf("query not narrowed to camera %s: %s", camera, trips[0].SQL)
+			}
+		})
+	}
+}
+
+func TestTripQueriesHonorPolygonID(t *testing.T) {
+	polygon := uuid.New()
+	scope := model.Scope{Type: model.ScopeCity}
+	filter := testFilter()
+	filter.PolygonID = &polygon
+
+	queries := map[string]struct {
+		run      func(context.Context, *AnalyticsRepository) error
+		fragment string
+		column   string
+	}{
+		"series": {func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TripSeries(ctx, scope, filter)
+			return err
+		}, "FROM mv_trip_daily mv", "mv.polygon_id = "},
+		"volume series": {func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TripVolumeSeries(ctx, scope, filter)
+			return err
+		}, "FROM mv_trip_daily mv", "mv.polygon_id = "},
+		"top drivers": {func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TopDrivers(ctx, scope, filter, 5, model.RankByCount)
+			return err
+		}, "FROM trips tr", "tr.polygon_id = "},
+		"top contractors": {func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TopContractors(ctx, scope, filter, 5, model.RankByCount)
+			return err
+		}, "FROM trips tr", "tr.polygon_id = "},
+		"duration stats": {func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TripDurationStats(ctx, scope, filter)
+			return err
+		}, "FROM trips tr", "tr.polygon_id = "},
+		"volume stats": {func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TripVolumeStats(ctx, scope, filter)
+			return err
+		}, "FROM trips tr", "tr.polygon_id = "},
+		"status breakdown": {func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TripStatusBreakdown(ctx, scope, filter)
+			return err
+		}, "FROM trips tr", "tr.polygon_id = "},
+		"driver performance": {func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.DriverPerformance(ctx, scope, filter, 5, model.SortOrder{})
+			return err
+		}, "FROM trips tr", "tr.polygon_id = "},
+		"driver kpis": {func(ctx context.Context, r *AnalyticsRepository) error {
+			_, _, err := r.DriverKPIs(ctx, scope, filter, model.Pagination{Limit: 10})
+			return err
+		}, "FROM trips tr", "tr.polygon_id = "},
+		"vehicle kpis": {func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.VehicleKPIs(ctx, scope, filter)
+			return err
+		}, "FROM trips tr", "tr.polygon_id = "},
+		"peak hours": {func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.PeakHours(ctx, scope, filter, 5)
+			return err
+		}, "FROM trips tr", "tr.polygon_id = "},
+		"heatmap": {func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.TripHeatmap(ctx, scope, filter)
+			return err
+		}, "FROM trips tr", "tr.polygon_id = "},
+		"fill-rate distribution": {func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.FillRateDistribution(ctx, scope, filter)
+			return err
+		}, "FROM trips tr", "tr.polygon_id = "},
+		"driver count series": {func(ctx context.Context, r *AnalyticsRepository) error {
+			_, err := r.DriverCountSeries(ctx, scope, filter, 5)
+			return err
+		}, "FROM mv_trip_daily mv", "mv.polygon_id = "},
+	}
+	for name, tt := range queries {
+		t.Run(name, func(t *testing.T) {
+			repo, rec := newRecordingRepo(t)
+			if err := tt.run(context.Background(), repo); err != nil {
+				t.Fatal(err)
+			}
+			found := rec.find(tt.fragment)
+			if len(found) == 0 {
+				t.Fatalf("no query on %q", tt.fragment)
+			}
+			if got, ok := found[0].boundTo(tt.column); !ok || got != polygon.String() {
+				t.Errorf("query not narrowed to polygon %s: %s", polygon, found[0].SQL)
+			}
+		})
+	}
+}
+
+func TestCameraLoadAndMapStatesShareTheCameraSubquery(t *testing.T) {
+	orgs := []uuid.UUID{uuid.New(), uuid.New()}
+	scope := model.Scope{Type: model.ScopeContractor, OrgID: &orgs[0], ContractorIDs: orgs, OrganizationIDs: orgs}
+	rng := testFilter().Range
+
+	repo, rec := newRecordingRepo(t)
+	if _, err := repo.CameraLoad(context.Background(), scope, rng, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := repo.MapStates(context.Background(), scope, rng, time.Time{}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	queries := rec.find("FROM cameras c")
+	if len(queries) != 2 {
+		t.Fatalf("got %d queries on cameras, want 2", len(queries))
+	}
+	loadSQL, loadArgs, ok := queries[0].subquery("c.id IN (")
+	if !ok {
+		t.Fatalf("camera load not narrowed to the scope's cameras: %s", queries[0].SQL)
+	}
+	mapSQL, mapArgs, ok := queries[1].subquery("c.id IN (")
+	if !ok {
+		t.Fatalf("map cameras not narrowed to the scope's cameras: %s", queries[1].SQL)
+	}
+	if loadSQL != mapSQL {
+		t.Errorf("camera subqueries differ:\n%s\n%s", loadSQL, mapSQL)
+	}
+	want := []interface{}{rng.From, rng.To, orgs[0].String(), orgs[1].String()}
+	for name, args := range map[string][]interface{}{"camera load": loadArgs, "map": mapArgs} {
+		if len(args) != len(want) {
+			t.Fatalf("%s binds %v, want %v", name, args, want)
+		}
+		for i := range want {
+			if fmt.Sprint(args[i]) != fmt.Sprint(want[i]) {
+				t.Errorf("%s binds %v, want %v", name, args, want)
+				break
+			}
+		}
+	}
+}
+
+func TestScopesBindEveryOrganizationOfAMultiOrgKgu(t *testing.T) {
+	orgs := []uuid.UUID{uuid.New(), uuid.New()}
+	contractors := []uuid.UUID{uuid.New()}
+	withContractors := model.Scope{Type: model.ScopeKgu, OrgID: &orgs[0], OrganizationIDs: orgs, ContractorIDs: contractors}
+	ownOnly := model.Scope{Type: model.ScopeKgu, OrgID: &orgs[0], OrganizationIDs: orgs}
+
+	tests := []struct {
+		name  string
+		apply func(*gorm.DB, model.Scope) *gorm.DB
+		scope model.Scope
+		where string
+		args  []uuid.UUID
+	}{
+		{"trips", applyTripScope, withContractors, "(t.created_by_org_id IN ($1,$2) OR t.contractor_id IN ($3))", append(orgs, contractors...)},
+		{"trips without contractors", applyTripScope, ownOnly, "t.created_by_org_id IN ($1,$2)", orgs},
+		{"tickets", applyTicketScope, withContractors, "t.created_by_org_id IN ($1,$2)", orgs},
+		{"trip views", applyMVTripScope, withContractors, "(mv.created_by_org_id IN ($1,$2) OR mv.contractor_id IN ($3))", append(orgs, contractors...)},
+		{"trip views without contractors", applyMVTripScope, ownOnly, "mv.created_by_org_id IN ($1,$2)", orgs},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, rec := newRecordingDB(t)
+			var rows []struct{ ID uuid.UUID }
+			if err := tt.apply(db.Table("scoped s"), tt.scope).Scan(&rows).Error; err != nil {
+				t.Fatal(err)
+			}
+			queries := rec.find("FROM scoped s")
+			if len(queries) != 1 || !strings.Contains(queries[0].SQL, tt.where) {
+				t.Fatalf("want %q in %v", tt.where, queries)
+			}
+			if len(queries[0].Args) != len(tt.args) {
+				t.Fatalf("got %d arguments, want %d", len(queries[0].Args), len(tt.args))
+			}
+			for i, id := range tt.args {
+				if queries[0].Args[i].Value != id.String() {
+					t.Errorf("argument %d is %v, want %s", i+1, queries[0].Args[i].Value, id)
+				}
+			}
+		})
+	}
+}
+
+func TestUtilizationIsTheShareOfActiveDays(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		activeDays int64
+		rng        model.DateRange
+		want       float64
+	}{
+		{"whole days", 3, model.DateRange{From: from, To: from.AddDate(0, 0, 10)}, 0.3},
+		{"partial day counts as a whole one", 1, model.DateRange{From: from, To: from.Add(36 * time.Hour)}, 0.5},
+		{"zero-length range is one day", 1, model.DateRange{From: from, To: from}, 1},
+		{"no active days", 0, model.DateRange{From: from, To: from}, 0},
+		{"more local dates than days is capped", 2, model.DateRange{From: from.Add(20 * time.Hour), To: from.Add(30 * time.Hour)}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := utilization(tt.activeDays, tt.rng); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeriveContractResultAtBoundaries(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 10)
+	tests := []struct {
+		name string
+		in   contractProgressInput
+		want string
+	}{
+		{"now at start with nothing hauled", contractProgressInput{start: start, end: end, now: start, minimalVolume: 100}, model.ContractResultOnTrack},
+		{"now at end short of the minimum", contractProgressInput{start: start, end: end, now: end, totalVolume: 99, minimalVolume: 100}, model.ContractResultBehind},
+		{"now at end with the minimum", contractProgressInput{start: start, end: end, now: end, totalVolume: 100, minimalVolume: 100}, model.ContractResultOnTrack},
+		{"zero-length period short of the minimum", contractProgressInput{start: start, end: start, now: start, totalVolume: 50, minimalVolume: 100}, model.ContractResultBehind},
+		{"zero-length period with the minimum", contractProgressInput{start: start, end: start, now: start, totalVolume: 100, minimalVolume: 100}, model.ContractResultOnTrack},
+		{"cost equal to the budget", contractProgressInput{start: start, end: end, now: start, minimalVolume: 100, totalCost: 500, budgetTotal: 500}, model.ContractResultOnTrack},
+		{"cost over the budget", contractProgressInput{start: start, end: end, now: start, minimalVolume: 100, totalCost: 500.01, budgetTotal: 500}, model.ContractResultOverBudget},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deriveContractResult(model.ContractStatusActive, tt.in); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestElapsedContractDaysAtBoundaries(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 10)
+	tests := []struct {
+		name            string
+		start, end, now time.Time
+		want            float64
+	}{
+		{"before the start", start, end, start.Add(-time.Hour), 0},
+		{"now at start", start, end, start, 1},
+		{"partial day", start, end, start.Add(36 * time.Hour), 2},
+		{"now at end", start, end, end, 10},
+		{"after the end", start, end, end.AddDate(0, 0, 5), 10},
+		{"zero-length period", start, start, start.AddDate(0, 0, 1), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := elapsedContractDays(tt.start, tt.end, tt.now); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTripLeadersRankByTheChosenMetric(t *testing.T) {
+	leaders := map[string]func(context.Context, *AnalyticsRepository, string) error{
+		"top drivers": func(ctx context.Context, r *AnalyticsRepository, rankBy string) error {
+			_, err := r.TopDrivers(ctx, model.Scope{Type: model.ScopeCity}, testFilter(), 5, rankBy)
+			return err
+		},
+		"top contractors": func(ctx context.Context, r *AnalyticsRepository, rankBy string) error {
+			_, err := r.TopContractors(ctx, model.Scope{Type: model.ScopeCity}, testFilter(), 5, rankBy)
+			return err
+		},
+	}
+	orders := map[string]string{
+		model.RankByCount:  "ORDER BY count DESC LIMIT",
+		model.RankByVolume: "ORDER BY volume DESC, count DESC LIMIT",
+	}
+	for name, run := range leaders {
+		for rankBy, order := range orders {
+			t.Run(name+" by "+rankBy, func(t *testing.T) {
+				repo, rec := newRecordingRepo(t)
+				if err := run(context.Background(), repo, rankBy); err != nil {
+					t.Fatal(err)
+				}
+				queries := rec.find("FROM trips tr")
+				if len(queries) != 1 || !strings.Contains(queries[0].SQL, order) {
+					t.Fatalf("want %q in %v", order, queries)
+				}
+			})
+		}
+	}
+}
+
+func TestLeaderSharesFollowTheRankedMetric(t *testing.T) {
+	leaders := func() []model.EntityMetric {
+		return []model.EntityMetric{{Count: 3, Volume: 10}, {Count: 1, Volume: 30}}
+	}
+	tests := []struct {
+		rankBy string
+		want   []float64
+	}{
+		{model.RankByCount, []float64{0.75, 0.25}},
+		{model.RankByVolume, []float64{0.25, 0.75}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rankBy, func(t *testing.T) {
+			items := leaders()
+			setLeaderShares(items, tt.rankBy)
+			for i, want := range tt.want {
+				if math.Abs(items[i].Share-want) > 1e-9 {
+					t.Errorf("leader %d has share %v, want %v", i, items[i].Share, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDriverCountSeriesHonorsDriverAndVehicle(t *testing.T) {
+	driver, vehicle := uuid.New(), uuid.New()
+	filter := testFilter()
+	filter.DriverID = &driver
+	filter.VehicleID = &vehicle
+	hourly := filter
+	hourly.GroupBy = model.GroupByHour
+
+	tests := map[string]struct {
+		filter   model.AnalyticsFilter
+		fragment string
+		prefix   string
+	}{
+		"daily view": {filter, "FROM mv_trip_daily mv", "mv."},
+		"raw trips":  {hourly, "FROM trips tr", "tr."},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo, rec := newRecordingRepo(t)
+			if _, err := repo.DriverCountSeries(context.Background(), model.Scope{Type: model.ScopeCity}, tt.filter, 5); err != nil {
+				t.Fatal(err)
+			}
+			found := rec.find(tt.fragment)
+			if len(found) == 0 {
+				t.Fatalf("no query on %q", tt.fragment)
+			}
+			if got, ok := found[0].boundTo(tt.prefix + "driver_id = "); !ok || got != driver.String() {
+				t.Errorf("query not narrowed to driver %s: %s", driver, found[0].SQL)
+			}
+			if got, ok := found[0].boundTo(tt.prefix + "vehicle_id = "); !ok || got != vehicle.String() {
+				t.Errorf("query not narrowed to vehicle %s: %s", vehicle, found[0].SQL)
+			}
+		})
+	}
+}
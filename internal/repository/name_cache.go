@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"analytics-service/internal/model"
+)
+
+// nameSource is a table the leaderboards take entity names from, with the
+// name used when an id has no row.
+type nameSource struct {
+	table    string
+	column   string
+	fallback string
+}
+
+var (
+	driverNames     = nameSource{table: "drivers", column: "full_name", fallback: "Driver"}
+	contractorNames = nameSource{table: "organizations", column: "name", fallback: "Contractor"}
+)
+
+type nameCacheKey struct {
+	table string
+	id    uuid.UUID
+}
+
+type nameCacheEntry struct {
+	name      string
+	expiresAt time.Time
+}
+
+// nameCache keeps entity names in memory so leaderboards can skip the name
+// joins. Names change rarely and there are few entities, so entries only
+// expire after ttl and the cache is not size bounded.
+type nameCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[nameCacheKey]nameCacheEntry
+}
+
+func newNameCache(ttl time.Duration) *nameCache {
+	return &nameCache{ttl: ttl, entries: make(map[nameCacheKey]nameCacheEntry)}
+}
+
+// get returns the cached names of ids and the ids it has no live entry for.
+func (c *nameCache) get(source nameSource, ids []uuid.UUID) (map[uuid.UUID]string, []uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	names := make(map[uuid.UUID]string, len(ids))
+	var missing []uuid.UUID
+	for _, id := range ids {
+		key := nameCacheKey{table: source.table, id: id}
+		entry, ok := c.entries[key]
+		if !ok || now.After(entry.expiresAt) {
+			delete(c.entries, key)
+			missing = append(missing, id)
+			continue
+		}
+		names[id] = entry.name
+	}
+	return names, missing
+}
+
+func (c *nameCache) put(source nameSource, names map[uuid.UUID]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := time.Now().Add(c.ttl)
+	for id, name := range names {
+		c.entries[nameCacheKey{table: source.table, id: id}] = nameCacheEntry{name: name, expiresAt: expiresAt}
+	}
+}
+
+// fillNames sets the Name of each metric from the name cache, loading the
+// misses from source in one query. Ids without a row get source.fallback and
+// are not cached, so they resolve once the row appears.
+func (r *AnalyticsRepository) fillNames(ctx context.Context, source nameSource, metrics []model.EntityMetric) error {
+	ids := make([]uuid.UUID, 0, len(metrics))
+	for _, metric := range metrics {
+		ids = append(ids, metric.ID)
+	}
+
+	names, missing := r.names.get(source, ids)
+	if len(missing) > 0 {
+		var rows []struct {
+			ID   uuid.UUID
+			Name string
+		}
+		err := r.db.WithContext(ctx).
+			Table(source.table).
+			Select(fmt.Sprintf("id, %s AS name", source.column)).
+			Where(fmt.Sprintf("id IN ? AND %s IS NOT NULL", source.column), missing).
+			Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+		loaded := make(map[uuid.UUID]string, len(rows))
+		for _, row := range rows {
+			loaded[row.ID] = row.Name
+			names[row.ID] = row.Name
+		}
+		r.names.put(source, loaded)
+	}
+
+	for i := range metrics {
+		name, ok := names[metrics[i].ID]
+		if !ok {
+			name = source.fallback
+		}
+		metrics[i].Name = name
+	}
+	return nil
+}
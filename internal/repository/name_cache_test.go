@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"analytics-service/internal/model"
+)
+
+// BenchmarkFillNames resolves the names of a large leaderboard with a warm
+// cache and with a cold one, which has to look every name up. The recorder
+// answers instantly, so the cold numbers are a lower bound: a real database
+// adds the round trip and the index lookups on top.
+func BenchmarkFillNames(b *testing.B) {
+	const leaders = 1000
+	metrics := make([]model.EntityMetric, leaders)
+	names := make(map[uuid.UUID]string, leaders)
+	for i := range metrics {
+		metrics[i].ID = uuid.New()
+		names[metrics[i].ID] = "Driver"
+	}
+	repo, rec := newRecordingRepo(b)
+	ctx := context.Background()
+
+	b.Run("cached", func(b *testing.B) {
+		repo.names = newNameCache(time.Hour)
+		repo.names.put(driverNames, names)
+		for i := 0; i < b.N; i++ {
+			if err := repo.fillNames(ctx, driverNames, metrics); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if n := len(rec.find(`FROM "drivers"`)); n != 0 {
+			b.Fatalf("warm cache sent %d queries", n)
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			repo.names = newNameCache(time.Hour)
+			if err := repo.fillNames(ctx, driverNames, metrics); err != nil {
+				b.Fatal(err)
+			}
+			rec.reset()
+		}
+	})
+}
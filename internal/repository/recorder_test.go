@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordedQuery is one statement sent to the recording driver, with its
+// arguments in placeholder order.
+type recordedQuery struct {
+	SQL  string
+	Args []driver.NamedValue
+}
+
+// recorder is a database/sql driver that answers every relation lookup with
+// true and every other query with no rows, keeping the statements so tests
+// can check which predicates a repository method sends.
+type recorder struct {
+	mu      sync.Mutex
+	queries []recordedQuery
+}
+
+var recorderSeq atomic.Int64
+
+// newRecordingRepo returns a repository backed by a fresh recorder.
+func newRecordingRepo(t testing.TB) (*AnalyticsRepository, *recorder) {
+	t.Helper()
+	db, rec := newRecordingDB(t)
+	return NewAnalyticsRepository(db, zerolog.Nop(), AnalyticsOptions{}), rec
+}
+
+// newRecordingDB opens a gorm connection to a fresh recorder.
+func newRecordingDB(t testing.TB) (*gorm.DB, *recorder) {
+	t.Helper()
+	rec := &recorder{}
+	name := fmt.Sprintf("recorder-%d", recorderSeq.Add(1))
+	sql.Register(name, rec)
+	sqlDB, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, rec
+}
+
+// find returns the recorded queries containing fragment.
+func (r *recorder) find(fragment string) []recordedQuery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []recordedQuery
+	for _, q := range r.queries {
+		if strings.Contains(q.SQL, fragment) {
+			found = append(found, q)
+		}
+	}
+	return found
+}
+
+// reset forgets the recorded queries.
+func (r *recorder) reset() {
+	r.mu.Lock()
+	r.queries = nil
+	r.mu.Unlock()
+}
+
+func (r *recorder) Open(string) (driver.Conn, error) { return &recorderConn{rec: r}, nil }
+
+type recorderConn struct{ rec *recorder }
+
+func (c *recorderConn) Prepare(string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepare is not supported")
+}
+func (c *recorderConn) Close() error              { return nil }
+func (c *recorderConn) Begin() (driver.Tx, error) { return recorderTx{}, nil }
+
+func (c *recorderConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.rec.mu.Lock()
+	c.rec.queries = append(c.rec.queries, recordedQuery{SQL: query, Args: args})
+	c.rec.mu.Unlock()
+	if strings.Contains(query, "pg_catalog.pg_class") || strings.Contains(query, "information_schema.columns") {
+		return &recorderRows{columns: []string{"exists"}, values: [][]driver.Value{{true}}}, nil
+	}
+	return &recorderRows{}, nil
+}
+
+func (c *recorderConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.rec.mu.Lock()
+	c.rec.queries = append(c.rec.queries, recordedQuery{SQL: query, Args: args})
+	c.rec.mu.Unlock()
+	return driver.RowsAffected(0), nil
+}
+
+type recorderTx struct{}
+
+func (recorderTx) Commit() error   { return nil }
+func (recorderTx) Rollback() error { return nil }
+
+type recorderRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *recorderRows) Columns() []string { return r.columns }
+func (r *recorderRows) Close() error      { return nil }
+func (r *recorderRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// boundTo returns the argument bound right after fragment, e.g. the value of
+// "t.contractor_id = $3" for fragment "t.contractor_id = ".
+func (q recordedQuery) boundTo(fragment string) (interface{}, bool) {
+	i := strings.Index(q.SQL, fragment+"$")
+	if i < 0 {
+		return nil, false
+	}
+	var n int
+	if _, err := fmt.Sscanf(q.SQL[i+len(fragment)+1:], "%d", &n); err != nil || n < 1 || n > len(q.Args) {
+		return nil, false
+	}
+	return q.Args[n-1].Value, true
+}
+
+// subquery returns the parenthesized SQL opening with fragment, with its
+// placeholders replaced by ? so subqueries of different statements compare
+// equal, and the arguments bound to them in order.
+func (q recordedQuery) subquery(fragment string) (string, []interface{}, bool) {
+	start := strings.Index(q.SQL, fragment)
+	if start < 0 {
+		return "", nil, false
+	}
+	start += len(fragment)
+	depth, end := 1, start
+	for ; end < len(q.SQL) && depth > 0; end++ {
+		switch q.SQL[end] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+	}
+	if depth > 0 {
+		return "", nil, false
+	}
+	var args []interface{}
+	sql := placeholder.ReplaceAllStringFunc(q.SQL[start:end-1], func(p string) string {
+		var n int
+		fmt.Sscanf(p, "$%d", &n)
+		if n >= 1 && n <= len(q.Args) {
+			args = append(args, q.Args[n-1].Value)
+		}
+		return "?"
+	})
+	return sql, args, true
+}
+
+var placeholder = regexp.MustCompile(`\$\d+`)
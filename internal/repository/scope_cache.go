@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"analytics-service/internal/model"
+)
+
+type scopeCacheKey struct {
+	userID uuid.UUID
+	// orgs joins the principal's organizations, so a token listing other
+	// organizations resolves anew.
+	orgs string
+	role model.UserRole
+}
+
+func newScopeCacheKey(principal model.Principal) scopeCacheKey {
+	orgs := principal.Organizations()
+	ids := make([]string, len(orgs))
+	for i, id := range orgs {
+		ids[i] = id.String()
+	}
+	return scopeCacheKey{userID: principal.UserID, orgs: strings.Join(ids, ","), role: principal.Role}
+}
+
+type scopeCacheEntry struct {
+	key       scopeCacheKey
+	scope     model.Scope
+	expiresAt time.Time
+}
+
+// scopeCache is a size-bounded LRU of resolved scopes whose entries expire
+// after ttl. Org membership changes rarely, so expiry is the only
+// invalidation.
+type scopeCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	size    int
+	order   *list.List
+	entries map[scopeCacheKey]*list.Element
+}
+
+func newScopeCache(ttl time.Duration, size int) *scopeCache {
+	return &scopeCache{
+		ttl:     ttl,
+		size:    size,
+		order:   list.New(),
+		entries: make(map[scopeCacheKey]*list.Element, size),
+	}
+}
+
+func (c *scopeCache) get(key scopeCacheKey) (model.Scope, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return model.Scope{}, false
+	}
+	entry := elem.Value.(*scopeCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return model.Scope{}, false
+	}
+	c.order.MoveToFront(elem)
+	return cloneScope(entry.scope), true
+}
+
+func (c *scopeCache) put(key scopeCacheKey, scope model.Scope) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &scopeCacheEntry{key: key, scope: cloneScope(scope), expiresAt: time.Now().Add(c.ttl)}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*scopeCacheEntry).key)
+	}
+}
+
+func (c *scopeCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[scopeCacheKey]*list.Element, c.size)
+}
+
+// cloneScope copies the ID slices so callers cannot modify a cached scope.
+func cloneScope(scope model.Scope) model.Scope {
+	scope.OrganizationIDs = append([]uuid.UUID(nil), scope.OrganizationIDs...)
+	scope.ContractorIDs = append([]uuid.UUID(nil), scope.ContractorIDs...)
+	if scope.DriverID != nil {
+		driverID := *scope.DriverID
+		scope.DriverID = &driverID
+	}
+	return scope
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -14,48 +15,125 @@ const (
 	orgTypeContractor = "CONTRACTOR"
 )
 
+// defaultRoleScopes maps the built-in roles to scope types. TOO_ADMIN is the
+// deprecated alias of LANDFILL_ADMIN and resolves the same way.
+var defaultRoleScopes = map[model.UserRole]model.ScopeType{
+	model.UserRoleAkimatAdmin:     model.ScopeCity,
+	model.UserRoleAkimatUser:      model.ScopeCity,
+	model.UserRoleKguZkhAdmin:     model.ScopeKgu,
+	model.UserRoleKguZkhUser:      model.ScopeKgu,
+	model.UserRoleContractorAdmin: model.ScopeContractor,
+	model.UserRoleLandfillAdmin:   model.ScopeContractor,
+	model.UserRoleLandfillUser:    model.ScopeContractor,
+	model.UserRoleTooAdmin:        model.ScopeContractor,
+}
+
+// ScopeOptions extends role resolution without code changes.
+type ScopeOptions struct {
+	// RoleScopes adds or overrides role → scope type mappings.
+	RoleScopes map[model.UserRole]model.ScopeType
+	// DefaultScope applies to roles without a mapping; empty denies them.
+	DefaultScope model.ScopeType
+	// CacheTTL keeps resolved scopes in memory for this long; zero disables
+	// the cache.
+	CacheTTL time.Duration
+	// CacheSize bounds the number of cached principals.
+	CacheSize int
+}
+
 type ScopeRepository struct {
-	db *gorm.DB
+	db           *gorm.DB
+	roleScopes   map[model.UserRole]model.ScopeType
+	defaultScope model.ScopeType
+	cache        *scopeCache
 }
 
 var ErrScopeUnsupported = errors.New("principal role is not allowed in analytics")
 
-func NewScopeRepository(db *gorm.DB) *ScopeRepository {
-	return &ScopeRepository{db: db}
+func NewScopeRepository(db *gorm.DB, opts ScopeOptions) *ScopeRepository {
+	roleScopes := make(map[model.UserRole]model.ScopeType, len(defaultRoleScopes)+len(opts.RoleScopes))
+	for role, scopeType := range defaultRoleScopes {
+		roleScopes[role] = scopeType
+	}
+	for role, scopeType := range opts.RoleScopes {
+		roleScopes[role] = scopeType
+	}
+	repo := &ScopeRepository{db: db, roleScopes: roleScopes, defaultScope: opts.DefaultScope}
+	if opts.CacheTTL > 0 && opts.CacheSize > 0 {
+		repo.cache = newScopeCache(opts.CacheTTL, opts.CacheSize)
+	}
+	return repo
+}
+
+// ClearCache drops all cached scopes.
+func (r *ScopeRepository) ClearCache() {
+	if r.cache != nil {
+		r.cache.clear()
+	}
 }
 
 func (r *ScopeRepository) ResolveScope(ctx context.Context, principal model.Principal) (model.Scope, error) {
+	// Drivers only ever see their own trips, whatever the configuration
+	// says, and need the driver_id claim for that. The scope needs no lookup,
+	// so it is not cached.
+	if principal.IsDriver() {
+		if !principal.HasDriverIdentity() {
+			return model.Scope{}, ErrScopeUnsupported
+		}
+		driverID := *principal.DriverID
+		return model.Scope{Type: model.ScopeDriver, DriverID: &driverID}, nil
+	}
+
+	if r.cache == nil {
+		return r.resolveScope(ctx, principal)
+	}
+	key := newScopeCacheKey(principal)
+	if scope, ok := r.cache.get(key); ok {
+		return scope, nil
+	}
+	scope, err := r.resolveScope(ctx, principal)
+	if err != nil {
+		return model.Scope{}, err
+	}
+	r.cache.put(key, scope)
+	return scope, nil
+}
+
+func (r *ScopeRepository) resolveScope(ctx context.Context, principal model.Principal) (model.Scope, error) {
+	scopeType, ok := r.roleScopes[principal.Role]
+	if !ok {
+		scopeType = r.defaultScope
+	}
+
 	scope := model.Scope{}
 
-	switch {
-	case principal.IsAkimat():
+	switch scopeType {
+	case model.ScopeCity:
 		scope.Type = model.ScopeCity
 		return scope, nil
-	case principal.IsKgu():
+	case model.ScopeKgu:
+		// A KGU user in several organizations sees the tickets of all of
+		// them and of all their contractors.
+		orgs := principal.Organizations()
 		scope.Type = model.ScopeKgu
-		scope.OrgID = &principal.OrgID
-		contractors, err := r.listContractors(ctx, principal.OrgID)
+		scope.OrgID = &orgs[0]
+		contractors, err := r.listContractors(ctx, orgs)
 		if err != nil {
 			return model.Scope{}, err
 		}
 		scope.ContractorIDs = contractors
-		scope.OrganizationIDs = append([]uuid.UUID{principal.OrgID}, contractors...)
+		scope.OrganizationIDs = orgs
 		scope.IncludeContractors = true
 		return scope, nil
-	case principal.IsContractor():
+	case model.ScopeContractor:
+		// Contractors and landfills see only their own organizations' data.
+		orgs := principal.Organizations()
 		scope.Type = model.ScopeContractor
-		scope.OrgID = &principal.OrgID
-		scope.ContractorIDs = []uuid.UUID{principal.OrgID}
-		scope.OrganizationIDs = []uuid.UUID{principal.OrgID}
+		scope.OrgID = &orgs[0]
+		scope.ContractorIDs = orgs
+		scope.OrganizationIDs = append([]uuid.UUID(nil), orgs...)
 		return scope, nil
-	case principal.IsLandfill():
-		// Landfill sees only their own organization's data (similar to contractor)
-		scope.Type = model.ScopeContractor
-		scope.OrgID = &principal.OrgID
-		scope.ContractorIDs = []uuid.UUID{principal.OrgID}
-		scope.OrganizationIDs = []uuid.UUID{principal.OrgID}
-		return scope, nil
-	case principal.IsToo():
+	case model.ScopeTechnical:
 		scope.Type = model.ScopeTechnical
 		scope.TechnicalOnly = true
 		return scope, nil
@@ -64,7 +142,21 @@ func (r *ScopeRepository) ResolveScope(ctx context.Context, principal model.Prin
 	}
 }
 
-func (r *ScopeRepository) listContractors(ctx context.Context, parent uuid.UUID) ([]uuid.UUID, error) {
+// OrganizationExists reports whether id is a known organization.
+func (r *ScopeRepository) OrganizationExists(ctx context.Context, id uuid.UUID) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Table("organizations").
+		Where("id = ?", id).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// listContractors returns the active contractors of the parent
+// organizations.
+func (r *ScopeRepository) listContractors(ctx context.Context, parents []uuid.UUID) ([]uuid.UUID, error) {
 	rows := make([]uuid.UUID, 0)
 	type result struct {
 		ID uuid.UUID
@@ -73,7 +165,7 @@ func (r *ScopeRepository) listContractors(ctx context.Context, parent uuid.UUID)
 	if err := r.db.WithContext(ctx).
 		Table("organizations").
 		Select("id").
-		Where("parent_org_id = ? AND type = ? AND is_active = ?", parent, orgTypeContractor, true).
+		Where("parent_org_id IN ? AND type = ? AND is_active = ?", parents, orgTypeContractor, true).
 		Find(&data).Error; err != nil {
 		return nil, err
 	}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"analytics-service/internal/model"
+)
+
+func TestResolveScopeCoversEveryOrganizationOfAKgu(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	repo := NewScopeRepository(db, ScopeOptions{})
+	principal := model.Principal{UserID: uuid.New(), OrgID: uuid.New(), OrgIDs: []uuid.UUID{uuid.New(), uuid.New()}, Role: model.UserRoleKguZkhUser}
+	// The primary organization repeated in OrgIDs must not be listed twice.
+	principal.OrgIDs = append(principal.OrgIDs, principal.OrgID)
+	orgs := principal.Organizations()
+
+	scope, err := repo.ResolveScope(context.Background(), principal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scope.Type != model.ScopeKgu || scope.OrgID == nil || *scope.OrgID != principal.OrgID {
+		t.Fatalf("got scope %+v, want a KGU scope of %s", scope, principal.OrgID)
+	}
+	if len(scope.OrganizationIDs) != 3 {
+		t.Fatalf("scope covers %v, want the 3 distinct organizations", scope.OrganizationIDs)
+	}
+
+	queries := rec.find(`FROM "organizations"`)
+	if len(queries) != 1 || !strings.Contains(queries[0].SQL, "parent_org_id IN ($1,$2,$3)") {
+		t.Fatalf("contractors not listed for every organization: %v", queries)
+	}
+	for i, id := range orgs {
+		if queries[0].Args[i].Value != id.String() {
+			t.Errorf("argument %d is %v, want %s", i+1, queries[0].Args[i].Value, id)
+		}
+	}
+}
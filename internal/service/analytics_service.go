@@ -3,12 +3,19 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"slices"
 	"sort"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
 
+	"analytics-service/internal/cache"
 	"analytics-service/internal/model"
 	"analytics-service/internal/repository"
 )
@@ -16,190 +23,1305 @@ import (
 var (
 	ErrPermissionDenied = errors.New("permission denied")
 	ErrNotFound         = errors.New("not found")
+	ErrInvalidInterval  = errors.New("invalid interval")
+	// ErrContractorRequired is returned when an endpoint needs a contractor
+	// and the scope does not imply one.
+	ErrContractorRequired = errors.New("contractor_id is required")
+	ErrInvalidSort        = errors.New("invalid sort")
+	// ErrTooManyMatches is returned when a name fragment matches more
+	// entities than maxContractorMatches.
+	ErrTooManyMatches = errors.New("too many matches")
+	// ErrRangeTooLarge is returned for ranges over the maximum when the
+	// caller asked for strict_range instead of clamping.
+	ErrRangeTooLarge = errors.New("date range too large")
+	// ErrInvalidOrgFilter is returned for a created_by_org_id outside city
+	// scope or naming an unknown organization.
+	ErrInvalidOrgFilter = errors.New("invalid created_by_org_id")
+	// ErrInvalidFilter is returned for filters that are valid on their own
+	// but contradict each other or the caller's scope.
+	ErrInvalidFilter = errors.New("invalid filter combination")
 )
 
+const (
+	maxSeriesEntities = 10
+	// rawTripsMaxRangeDays caps ranges for queries that scan the trips table
+	// instead of the daily materialized views.
+	rawTripsMaxRangeDays = 31
+	// hourlyMaxRangeDays caps ranges of group_by=hour series.
+	hourlyMaxRangeDays = 7
+	// maxIntervalPoints bounds the number of buckets an interval series may
+	// produce over the requested range.
+	maxIntervalPoints = 1000
+	// maxContractorMatches bounds how many contractors a contractor_name
+	// fragment may resolve to.
+	maxContractorMatches = 20
+	// maxDuplicateSamples bounds the duplicate trips listed by the quality
+	// report.
+	maxDuplicateSamples = 50
+	// Leader list sizes used when Options leaves them unset: top defaults to
+	// defaultLeaderTop for trips and violations and to defaultPerformanceTop
+	// for performance, and is clamped to maxTop.
+	defaultLeaderTop      = 5
+	defaultPerformanceTop = 10
+	maxTop                = 50
+)
+
+type Options struct {
+	DefaultRangeDays int
+	MaxRangeDays     int
+	// ActiveTripMaxAge bounds how long an open trip (exit_at IS NULL) still
+	// counts as active on the map; zero disables the cutoff.
+	ActiveTripMaxAge time.Duration
+	// DashboardCameraScopes lists the scope types that get camera load on the
+	// dashboard; other scopes receive an empty cameras list.
+	DashboardCameraScopes []model.ScopeType
+	// AreaNeglectAfter is the idle time after which a cleaning area is
+	// flagged as neglected; zero disables the flag.
+	AreaNeglectAfter time.Duration
+	// MaxNameLength truncates entity names to this many characters, keeping
+	// the original in the matching full-name field; zero disables it.
+	MaxNameLength int
+	// ViolationSeverities overrides the status → severity mapping of
+	// violations (HIGH, MEDIUM, LOW).
+	ViolationSeverities map[string]string
+	// Cache stores dashboard, trip, violation and performance responses;
+	// nil disables caching.
+	Cache *cache.Cache
+	// DedupInFlight lets concurrent identical dashboard, trip, violation and
+	// performance requests share one query run.
+	DedupInFlight bool
+	// DuplicateTripWindow is how close two trips of the same driver and
+	// vehicle must enter for the later one to count as a duplicate.
+	DuplicateTripWindow time.Duration
+	// DedupTrips leaves duplicate trips out of the dashboard counts.
+	DedupTrips bool
+	// DefaultLeaderLimit is the leader list size of trips and violations
+	// when top is omitted; zero keeps defaultLeaderTop.
+	DefaultLeaderLimit int
+	// DefaultPerformanceLimit is the list size of performance and volume
+	// efficiency when top is omitted; zero keeps defaultPerformanceTop.
+	DefaultPerformanceLimit int
+	// TonnesPerM3 converts volumes for unit=tonnes.
+	TonnesPerM3 float64
+	// ViolationRateMinTrips is the trip threshold of violation leaders
+	// ranked by rate.
+	ViolationRateMinTrips int64
+	// Log receives the debug filter log of requests whose context carries
+	// no logger.
+	Log zerolog.Logger
+}
+
 type AnalyticsService struct {
-	scopes       *repository.ScopeRepository
-	analytics    *repository.AnalyticsRepository
-	defaultRange int
-	maxRange     int
+	scopes           *repository.ScopeRepository
+	analytics        *repository.AnalyticsRepository
+	defaultRange     int
+	maxRange         int
+	activeTripMaxAge time.Duration
+	cameraScopes     map[model.ScopeType]bool
+	areaNeglectAfter time.Duration
+	maxNameLength    int
+	cache            *cache.Cache
+	severities       map[string]string
+	inflight         *singleflight.Group
+	duplicateWindow  time.Duration
+	dedupTrips       bool
+	leaderTop        int
+	performanceTop   int
+	tonnesPerM3      float64
+	rateMinTrips     int64
+	log              zerolog.Logger
+}
+
+func NewAnalyticsService(scopes *repository.ScopeRepository, analytics *repository.AnalyticsRepository, opts Options) *AnalyticsService {
+	cameraScopes := make(map[model.ScopeType]bool, len(opts.DashboardCameraScopes))
+	for _, scopeType := range opts.DashboardCameraScopes {
+		cameraScopes[scopeType] = true
+	}
+
+	severities := make(map[string]string, len(defaultViolationSeverities)+len(opts.ViolationSeverities))
+	for status, severity := range defaultViolationSeverities {
+		severities[status] = severity
+	}
+	for status, severity := range opts.ViolationSeverities {
+		severities[status] = severity
+	}
+
+	service := &AnalyticsService{
+		scopes:           scopes,
+		analytics:        analytics,
+		defaultRange:     opts.DefaultRangeDays,
+		maxRange:         opts.MaxRangeDays,
+		activeTripMaxAge: opts.ActiveTripMaxAge,
+		cameraScopes:     cameraScopes,
+		areaNeglectAfter: opts.AreaNeglectAfter,
+		maxNameLength:    opts.MaxNameLength,
+		cache:            opts.Cache,
+		severities:       severities,
+		duplicateWindow:  opts.DuplicateTripWindow,
+		dedupTrips:       opts.DedupTrips,
+		leaderTop:        clampTop(opts.DefaultLeaderLimit, defaultLeaderTop),
+		performanceTop:   clampTop(opts.DefaultPerformanceLimit, defaultPerformanceTop),
+		tonnesPerM3:      opts.TonnesPerM3,
+		rateMinTrips:     opts.ViolationRateMinTrips,
+		log:              opts.Log,
+	}
+	if opts.DedupInFlight {
+		service.inflight = &singleflight.Group{}
+	}
+	return service
+}
+
+// GetDashboard builds the dashboard for rng. A non-nil bbox limits the map
+// areas and polygons to the visible viewport, and a non-nil cameraID the
+// cameras section to that camera.
+func (s *AnalyticsService) GetDashboard(ctx context.Context, principal model.Principal, rng model.DateRange, bbox *model.BBox, cameraID *uuid.UUID) (*model.DashboardMetrics, error) {
+	scope, err := s.resolveSectionScope(ctx, principal, model.SectionDashboard)
+	if err != nil {
+		return nil, err
+	}
+
+	// The range is validated before the cache lookup: Strict is not part of
+	// the key, so a strict request must not be answered from a clamped entry.
+	rangeNormalized, err := s.normalizeRange(rng)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keys use the requested range rather than the normalized one: an open
+	// range ends at time.Now() and would never hit otherwise.
+	cacheKey := s.cache.Key("dashboard", scope, rng, bbox, cameraID)
+	var cached model.DashboardMetrics
+	if s.cache.Get(ctx, cacheKey, &cached) {
+		return &cached, nil
+	}
+	value, err := s.shared(ctx, cacheKey, func(ctx context.Context) (interface{}, error) {
+		return s.loadDashboard(ctx, scope, rangeNormalized, bbox, cameraID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*model.DashboardMetrics), nil
+}
+
+// loadDashboard runs the dashboard queries for a cache miss.
+func (s *AnalyticsService) loadDashboard(ctx context.Context, scope model.Scope, rangeNormalized model.DateRange, bbox *model.BBox, cameraID *uuid.UUID) (*model.DashboardMetrics, error) {
+	metrics := &model.DashboardMetrics{GeneratedFor: rangeNormalized, Meta: &model.DashboardMeta{}}
+	computedAt := &metrics.Meta.ComputedAt
+	metrics.Cameras = []model.CameraLoadMetric{}
+	// Technical scope sees no business data, so those sections are never
+	// queried; they are returned empty rather than null.
+	if scope.Type == model.ScopeTechnical {
+		metrics.Areas = []model.CleaningAreaActivity{}
+		metrics.Contractors = model.DashboardContractors{Active: []model.EntityMetric{}, Idle: []model.EntityMetric{}}
+		metrics.Contracts = []model.ContractProgress{}
+		metrics.Map = model.MapSummary{Areas: []model.MapAreaState{}, Polygons: []model.MapPolygonState{}, Cameras: []model.MapCameraState{}}
+	}
+
+	// The sections are independent queries, so they run concurrently. Each
+	// goroutine writes only its own fields; the first error cancels the rest
+	// and is returned as is.
+	g, gctx := errgroup.WithContext(ctx)
+	previousRange := previousPeriod(rangeNormalized)
+	var previous model.DashboardStats
+
+	if scope.Type != model.ScopeTechnical {
+		activeSince := s.activeTripCutoff()
+		g.Go(func() error {
+			stats, err := s.analytics.DashboardStats(gctx, scope, rangeNormalized, s.dedupWindow())
+			metrics.Stats = stats
+			computedAt.Stats = timeNow()
+			return err
+		})
+		g.Go(func() error {
+			var err error
+			previous, err = s.analytics.DashboardStats(gctx, scope, previousRange, s.dedupWindow())
+			return err
+		})
+		g.Go(func() error {
+			areas, err := s.analytics.CleaningAreaActivity(gctx, scope, rangeNormalized, activeSince, nil)
+			metrics.Areas = areas
+			computedAt.Areas = timeNow()
+			return err
+		})
+		g.Go(func() error {
+			active, idle, err := s.analytics.ContractorActivitySplit(gctx, scope, rangeNormalized)
+			s.shortenEntityNames(active)
+			s.shortenEntityNames(idle)
+			metrics.Contractors = model.DashboardContractors{Active: active, Idle: idle}
+			computedAt.Contractors = timeNow()
+			return err
+		})
+		g.Go(func() error {
+			contracts, err := s.analytics.ContractProgress(gctx, scope)
+			metrics.Contracts = contracts
+			computedAt.Contracts = timeNow()
+			return err
+		})
+		g.Go(func() error {
+			mapAreas, mapPolygons, mapCameras, err := s.analytics.MapStates(gctx, scope, rangeNormalized, activeSince, bbox)
+			metrics.Map = model.MapSummary{Areas: mapAreas, Polygons: mapPolygons, Cameras: mapCameras}
+			computedAt.Map = timeNow()
+			return err
+		})
+	}
+
+	if s.cameraScopes[scope.Type] {
+		g.Go(func() error {
+			cameraLoad, err := s.analytics.CameraLoad(gctx, scope, rangeNormalized, cameraID)
+			if err != nil {
+				return err
+			}
+			metrics.Cameras = cameraLoad
+			computedAt.Cameras = timeNow()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	if scope.Type != model.ScopeTechnical {
+		metrics.Stats.PreviousPeriod = &model.DashboardPeriodCompare{
+			Range:                   previousRange,
+			CompletedTrips:          previous.CompletedTrips,
+			Violations:              previous.Violations,
+			CompletedTripsChangePct: percentChange(metrics.Stats.CompletedTrips, previous.CompletedTrips),
+			ViolationsChangePct:     percentChange(metrics.Stats.Violations, previous.Violations),
+		}
+	}
+
+	metrics.Meta.GeneratedAt = time.Now().UTC()
+	return metrics, nil
+}
+
+// timeNow returns the current UTC time as a pointer, for optional timestamps.
+func timeNow() *time.Time {
+	now := time.Now().UTC()
+	return &now
+}
+
+// GetTripAnalytics builds the trip report. rankBy orders the leader lists
+// (model.RankByCount when empty or model.RankByVolume).
+func (s *AnalyticsService) GetTripAnalytics(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter, top int, rankBy string) (*model.TripAnalytics, error) {
+	scope, err := s.resolveSectionScope(ctx, principal, model.SectionTrips)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized, err := s.normalizeFilter(ctx, "GetTripAnalytics", scope, filter)
+	if err != nil {
+		return nil, err
+	}
+	if scope, err = s.orgFilterScope(ctx, scope, normalized); err != nil {
+		return nil, err
+	}
+	if normalized.SubDaily() && normalized.GroupByEntity != "" {
+		return nil, fmt.Errorf("%w: interval and group_by=hour cannot be combined with group_by_entity", ErrInvalidInterval)
+	}
+	if normalized.FromTrips() {
+		if normalized, err = limitTripSeries(normalized); err != nil {
+			return nil, err
+		}
+	}
+
+	top = clampTop(top, s.leaderTop)
+	if rankBy == "" {
+		rankBy = model.RankByCount
+	}
+	cacheKey := s.filterCacheKey("trips", scope, filter, top, rankBy)
+	var cached model.TripAnalytics
+	if s.cache.Get(ctx, cacheKey, &cached) {
+		return s.tripAnalyticsIn(&cached, filter.Unit), nil
+	}
+
+	value, err := s.shared(ctx, cacheKey, func(ctx context.Context) (interface{}, error) {
+		return s.loadTripAnalytics(ctx, scope, normalized, top, rankBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.tripAnalyticsIn(value.(*model.TripAnalytics), filter.Unit), nil
+}
+
+// loadTripAnalytics runs the trip analytics queries for a cache miss.
+func (s *AnalyticsService) loadTripAnalytics(ctx context.Context, scope model.Scope, normalized model.AnalyticsFilter, top int, rankBy string) (*model.TripAnalytics, error) {
+	series, err := s.analytics.TripSeries(ctx, scope, normalized)
+	if err != nil {
+		return nil, err
+	}
+	volumeSeries, err := s.analytics.TripVolumeSeries(ctx, scope, normalized)
+	if err != nil {
+		return nil, err
+	}
+	entitySeries, err := s.analytics.TripSeriesByEntity(ctx, scope, normalized, maxSeriesEntities)
+	if err != nil {
+		return nil, err
+	}
+	topDrivers, err := s.analytics.TopDrivers(ctx, scope, normalized, top, rankBy)
+	if err != nil {
+		return nil, err
+	}
+	topContractors, err := s.analytics.TopContractors(ctx, scope, normalized, top, rankBy)
+	if err != nil {
+		return nil, err
+	}
+	durationStats, err := s.analytics.TripDurationStats(ctx, scope, normalized)
+	if err != nil {
+		return nil, err
+	}
+	volumeStats, err := s.analytics.TripVolumeStats(ctx, scope, normalized)
+	if err != nil {
+		return nil, err
+	}
+	statusBreakdown, err := s.analytics.TripStatusBreakdown(ctx, scope, normalized)
+	if err != nil {
+		return nil, err
+	}
+	for i := range statusBreakdown {
+		if statusBreakdown[i].Type != model.TripStatusOK {
+			statusBreakdown[i].Severity = s.severityOf(statusBreakdown[i].Type)
+		}
+	}
+	s.shortenEntityNames(topDrivers)
+	s.shortenEntityNames(topContractors)
+
+	result := &model.TripAnalytics{
+		Series:          series,
+		SeriesSummary:   summarizeSeries(series),
+		EntitySeries:    entitySeries,
+		VolumeSeries:    volumeSeries,
+		VolumeSummary:   summarizeSeries(volumeSeries),
+		StatusBreakdown: statusBreakdown,
+		TopDrivers:      topDrivers,
+		TopContractors:  topContractors,
+		DurationStats:   durationStats,
+		VolumeStats:     volumeStats,
+	}
+	return result, nil
+}
+
+func (s *AnalyticsService) GetTripStatusSeries(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter) (*model.TripStatusSeries, error) {
+	if principal.IsDriver() {
+		return nil, ErrPermissionDenied
+	}
+
+	scope, err := s.scopes.ResolveScope(ctx, principal)
+	if err != nil || scope.Type == model.ScopeTechnical {
+		return nil, ErrPermissionDenied
+	}
+
+	normalized, err := s.normalizeFilter(ctx, "GetTripStatusSeries", scope, filter)
+	if err != nil {
+		return nil, err
+	}
+	if scope, err = s.orgFilterScope(ctx, scope, normalized); err != nil {
+		return nil, err
+	}
+	if normalized, err = limitTripSeries(normalized); err != nil {
+		return nil, err
+	}
+
+	series, err := s.analytics.TripStatusSeries(ctx, scope, normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	return &series, nil
+}
+
+func (s *AnalyticsService) GetDriverCountSeries(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter) (*model.DriverCountSeries, error) {
+	if principal.IsDriver() {
+		return nil, ErrPermissionDenied
+	}
+
+	scope, err := s.scopes.ResolveScope(ctx, principal)
+	if err != nil || scope.Type == model.ScopeTechnical {
+		return nil, ErrPermissionDenied
+	}
+
+	normalized, err := s.normalizeFilter(ctx, "GetDriverCountSeries", scope, filter)
+	if err != nil {
+		return nil, err
+	}
+	if scope, err = s.orgFilterScope(ctx, scope, normalized); err != nil {
+		return nil, err
+	}
+	if normalized.FromTrips() {
+		if normalized, err = limitTripSeries(normalized); err != nil {
+			return nil, err
+		}
+	}
+
+	series, err := s.analytics.DriverCountSeries(ctx, scope, normalized, maxSeriesEntities)
+	if err != nil {
+		return nil, err
+	}
+
+	return &series, nil
+}
+
+// GetKguComparison compares KGUs against each other. Only city-wide
+// principals may see it.
+func (s *AnalyticsService) GetKguComparison(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter) ([]model.KguComparison, error) {
+	if principal.IsDriver() {
+		return nil, ErrPermissionDenied
+	}
+
+	scope, err := s.scopes.ResolveScope(ctx, principal)
+	if err != nil || scope.Type != model.ScopeCity {
+		return nil, ErrPermissionDenied
+	}
+
+	normalized, err := s.normalizeFilter(ctx, "GetKguComparison", scope, filter)
+	if err != nil {
+		return nil, err
+	}
+	// The comparison is keyed by the creating KGU, so the narrowed scope is
+	// not needed: KguComparison keeps the chosen KGU's row only.
+	if _, err = s.orgFilterScope(ctx, scope, normalized); err != nil {
+		return nil, err
+	}
+	items, err := s.analytics.KguComparison(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []model.KguComparison{}
+	}
+	s.convertKguVolumes(items, filter.Unit)
+	return items, nil
+}
+
+// GetMapGeoJSON returns the cleaning areas with trips in rng as a GeoJSON
+// FeatureCollection for the map, limited to bbox when it is not nil.
+func (s *AnalyticsService) GetMapGeoJSON(ctx context.Context, principal model.Principal, rng model.DateRange, bbox *model.BBox) (*model.MapFeatureCollection, error) {
+	if principal.IsDriver() {
+		return nil, ErrPermissionDenied
+	}
+
+	scope, err := s.scopes.ResolveScope(ctx, principal)
+	if err != nil || scope.Type == model.ScopeTechnical {
+		return nil, ErrPermissionDenied
+	}
+
+	normalized, err := s.normalizeRange(rng)
+	if err != nil {
+		return nil, err
+	}
+	features, err := s.analytics.MapAreaFeatures(ctx, scope, normalized, s.activeTripCutoff(), bbox)
+	if err != nil {
+		return nil, err
+	}
+	if features == nil {
+		features = []model.MapAreaFeature{}
+	}
+	return &model.MapFeatureCollection{Type: "FeatureCollection", Features: features}, nil
+}
+
+// ResolveContractorName turns filter.ContractorName into ContractorIDs,
+// looking only at contractors visible to the principal. ContractorIDs given
+// by the caller are narrowed to the matching ones. It returns the matches
+// (empty when nothing matched, which then filters everything out) or nil
+// when no name was given.
+func (s *AnalyticsService) ResolveContractorName(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter) (model.AnalyticsFilter, []model.ContractorMatch, error) {
+	if filter.ContractorName == "" {
+		return filter, nil, nil
+	}
+	if principal.IsDriver() {
+		return filter, nil, ErrPermissionDenied
+	}
+
+	scope, err := s.scopes.ResolveScope(ctx, principal)
+	if err != nil {
+		return filter, nil, ErrPermissionDenied
+	}
+	// Technical users see no contractors; skip the lookup and let the
+	// endpoint apply its own technical-scope rule.
+	if scope.Type == model.ScopeTechnical {
+		filter.ContractorIDs = []uuid.UUID{}
+		return filter, []model.ContractorMatch{}, nil
+	}
+
+	matches, err := s.analytics.FindContractors(ctx, scope, filter.ContractorName, maxContractorMatches+1)
+	if err != nil {
+		return filter, nil, err
+	}
+	if len(matches) > maxContractorMatches {
+		return filter, nil, fmt.Errorf("%w: contractor_name %q matches more than %d contractors, use a longer fragment", ErrTooManyMatches, filter.ContractorName, maxContractorMatches)
+	}
+
+	requested := filter.ContractorIDs
+	filter.ContractorIDs = make([]uuid.UUID, 0, len(matches))
+	for _, match := range matches {
+		if requested == nil || slices.Contains(requested, match.ID) {
+			filter.ContractorIDs = append(filter.ContractorIDs, match.ID)
+		}
+	}
+	return filter, matches, nil
+}
+
+// GetContractorRankSeries returns a contractor's rank among its peers per
+// bucket. Contractor users always get their own organization; other scopes
+// must name a contractor they can see.
+func (s *AnalyticsService) GetContractorRankSeries(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter, byVolume bool) (*model.ContractorRankSeries, error) {
+	if principal.IsDriver() {
+		return nil, ErrPermissionDenied
+	}
+
+	scope, err := s.scopes.ResolveScope(ctx, principal)
+	if err != nil || scope.Type == model.ScopeTechnical {
+		return nil, ErrPermissionDenied
+	}
+
+	normalized, err := s.normalizeFilter(ctx, "GetContractorRankSeries", scope, filter)
+	if err != nil {
+		return nil, err
+	}
+	// Peers are ranked on the chosen KGU's tickets by ContractorRankSeries
+	// itself; the contractor check below stays on the caller's scope.
+	if _, err = s.orgFilterScope(ctx, scope, normalized); err != nil {
+		return nil, err
+	}
+	if normalized.SubDaily() {
+		return nil, fmt.Errorf("%w: rank series support day, week and month buckets only", ErrInvalidInterval)
+	}
+
+	var contractorID uuid.UUID
+	switch {
+	case scope.Type == model.ScopeContractor && normalized.ContractorID != nil && scope.AllowsContractor(*normalized.ContractorID):
+		// Users of several contractors pick one of theirs.
+		contractorID = *normalized.ContractorID
+	case scope.Type == model.ScopeContractor && scope.OrgID != nil:
+		contractorID = *scope.OrgID
+	case normalized.ContractorID != nil:
+		contractorID = *normalized.ContractorID
+	default:
+		return nil, ErrContractorRequired
+	}
+	if !scope.AllowsContractor(contractorID) {
+		return nil, ErrPermissionDenied
+	}
+
+	points, err := s.analytics.ContractorRankSeries(ctx, contractorID, normalized, byVolume)
+	if err != nil {
+		return nil, err
+	}
+
+	metric := "trips"
+	if byVolume {
+		metric = "volume"
+		s.convertRankValues(points, filter.Unit)
+	}
+	return &model.ContractorRankSeries{ContractorID: contractorID, Metric: metric, Points: points}, nil
 }
 
-func NewAnalyticsService(scopes *repository.ScopeRepository, analytics *repository.AnalyticsRepository, defaultRange, maxRange int) *AnalyticsService {
-	return &AnalyticsService{
-		scopes:       scopes,
-		analytics:    analytics,
-		defaultRange: defaultRange,
-		maxRange:     maxRange,
+// GetContractorDetails runs the trip, violation, driver and contract reports
+// for one contractor by pinning filter.ContractorID. The contractor must be
+// visible in the caller's scope.
+func (s *AnalyticsService) GetContractorDetails(ctx context.Context, principal model.Principal, contractorID uuid.UUID, filter model.AnalyticsFilter, top int) (*model.ContractorDetails, error) {
+	if principal.IsDriver() {
+		return nil, ErrPermissionDenied
+	}
+
+	scope, err := s.scopes.ResolveScope(ctx, principal)
+	if err != nil || scope.Type == model.ScopeTechnical {
+		return nil, ErrPermissionDenied
+	}
+	if !scope.AllowsContractor(contractorID) {
+		return nil, ErrPermissionDenied
+	}
+	exists, err := s.scopes.OrganizationExists(ctx, contractorID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	filter.ContractorID = &contractorID
+	filter.ContractorIDs = nil
+	filter.GroupByEntity = ""
+	normalized, err := s.normalizeFilter(ctx, "GetContractorDetails", scope, filter)
+	if err != nil {
+		return nil, err
+	}
+	if scope, err = s.orgFilterScope(ctx, scope, normalized); err != nil {
+		return nil, err
+	}
+	if normalized.FromTrips() {
+		if normalized, err = limitTripSeries(normalized); err != nil {
+			return nil, err
+		}
+	}
+	top = clampTop(top, s.leaderTop)
+
+	tripSeries, err := s.analytics.TripSeries(ctx, scope, normalized)
+	if err != nil {
+		return nil, err
+	}
+	volumeSeries, err := s.analytics.TripVolumeSeries(ctx, scope, normalized)
+	if err != nil {
+		return nil, err
+	}
+	violations, err := s.analytics.ViolationBreakdown(ctx, scope, normalized)
+	if err != nil {
+		return nil, err
+	}
+	topDrivers, err := s.analytics.TopDrivers(ctx, scope, normalized, top, model.RankByCount)
+	if err != nil {
+		return nil, err
+	}
+	contracts, err := s.analytics.ContractProgress(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+	s.shortenEntityNames(topDrivers)
+	contracts = filterContracts(contracts, func(c model.ContractProgress) bool {
+		return c.ContractorID == contractorID
+	})
+	s.convertContractVolumes(contracts, filter.Unit)
+
+	factor := s.volumeFactor(filter.Unit)
+	return &model.ContractorDetails{
+		ContractorID: contractorID,
+		Range:        normalized.Range,
+		TripSeries:   tripSeries,
+		VolumeSeries: convertSeries(volumeSeries, factor),
+		Violations:   violations,
+		TopDrivers:   convertEntityMetrics(topDrivers, factor),
+		Contracts:    contracts,
+	}, nil
+}
+
+func (s *AnalyticsService) GetPeakHours(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter, limit int) ([]model.PeakHour, error) {
+	if principal.IsDriver() {
+		return nil, ErrPermissionDenied
+	}
+
+	scope, err := s.scopes.ResolveScope(ctx, principal)
+	if err != nil || scope.Type == model.ScopeTechnical {
+		return nil, ErrPermissionDenied
+	}
+
+	normalized, err := s.normalizeFilter(ctx, "GetPeakHours", scope, filter)
+	if err != nil {
+		return nil, err
+	}
+	if scope, err = s.orgFilterScope(ctx, scope, normalized); err != nil {
+		return nil, err
+	}
+	normalized.Range = capRange(normalized.Range, rawTripsMaxRangeDays)
+
+	return s.analytics.PeakHours(ctx, scope, normalized, limit)
+}
+
+// GetTripHeatmap reads the raw trips table, so the range is capped like
+// GetPeakHours.
+func (s *AnalyticsService) GetTripHeatmap(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter) (*model.TripHeatmap, error) {
+	if principal.IsDriver() {
+		return nil, ErrPermissionDenied
+	}
+
+	scope, err := s.scopes.ResolveScope(ctx, principal)
+	if err != nil || scope.Type == model.ScopeTechnical {
+		return nil, ErrPermissionDenied
+	}
+
+	normalized, err := s.normalizeFilter(ctx, "GetTripHeatmap", scope, filter)
+	if err != nil {
+		return nil, err
+	}
+	if scope, err = s.orgFilterScope(ctx, scope, normalized); err != nil {
+		return nil, err
+	}
+	normalized.Range = capRange(normalized.Range, rawTripsMaxRangeDays)
+
+	heatmap, err := s.analytics.TripHeatmap(ctx, scope, normalized)
+	if err != nil {
+		return nil, err
+	}
+	return &heatmap, nil
+}
+
+// GetFillRateDistribution buckets the trips in range by vehicle fill rate.
+// It reads the raw trips table, so the range is capped like the heatmap.
+func (s *AnalyticsService) GetFillRateDistribution(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter) (*model.FillRateDistribution, error) {
+	if principal.IsDriver() {
+		return nil, ErrPermissionDenied
+	}
+
+	scope, err := s.scopes.ResolveScope(ctx, principal)
+	if err != nil || scope.Type == model.ScopeTechnical {
+		return nil, ErrPermissionDenied
+	}
+
+	normalized, err := s.normalizeFilter(ctx, "GetFillRateDistribution", scope, filter)
+	if err != nil {
+		return nil, err
+	}
+	if scope, err = s.orgFilterScope(ctx, scope, normalized); err != nil {
+		return nil, err
+	}
+	normalized.Range = capRange(normalized.Range, rawTripsMaxRangeDays)
+
+	distribution, err := s.analytics.FillRateDistribution(ctx, scope, normalized)
+	if err != nil {
+		return nil, err
+	}
+	return &distribution, nil
+}
+
+// ListTrips pages through raw trips, by offset or after page.After, or
+// returns the page.LastN most recent ones regardless of the range. Drivers
+// get their own trips only.
+func (s *AnalyticsService) ListTrips(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter, page model.Pagination) (*model.TripListPage, error) {
+	scope, err := s.resolveSectionScope(ctx, principal, model.SectionTripList)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.TripListPage{Items: []model.TripListItem{}, Limit: page.Limit, Offset: page.Offset}
+	if scope.Type == model.ScopeTechnical {
+		return result, nil
+	}
+
+	// last_n ignores the range, so it is dropped before normalizing: an
+	// oversized or reversed range must not reject the request.
+	if page.LastN > 0 {
+		filter.Range = model.DateRange{}
+	}
+	normalized, err := s.normalizeFilter(ctx, "ListTrips", scope, filter)
+	if err != nil {
+		return nil, err
+	}
+	if scope, err = s.orgFilterScope(ctx, scope, normalized); err != nil {
+		return nil, err
+	}
+
+	if page.LastN > 0 {
+		trips, err := s.analytics.LatestTrips(ctx, scope, normalized, page.LastN)
+		if err != nil {
+			return nil, err
+		}
+		s.convertTripListVolumes(trips, filter.Unit)
+		if trips != nil {
+			result.Items = trips
+		}
+		result.Total = int64(len(trips))
+		result.Limit = page.LastN
+		result.Offset = 0
+		return result, nil
+	}
+
+	trips, total, next, err := s.analytics.ListTrips(ctx, scope, normalized, page)
+	if err != nil {
+		return nil, err
+	}
+	s.convertTripListVolumes(trips, filter.Unit)
+
+	if trips != nil {
+		result.Items = trips
+	}
+	result.Total = total
+	if next != nil {
+		result.NextCursor = next.Encode()
+	}
+	return result, nil
+}
+
+func (s *AnalyticsService) GetTripDetails(ctx context.Context, principal model.Principal, tripID uuid.UUID) (*model.TripDetails, error) {
+	if principal.IsDriver() {
+		return nil, ErrPermissionDenied
+	}
+
+	scope, err := s.scopes.ResolveScope(ctx, principal)
+	if err != nil || scope.Type == model.ScopeTechnical {
+		return nil, ErrPermissionDenied
+	}
+
+	details, err := s.analytics.TripDetails(ctx, scope, tripID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return details, nil
+}
+
+// GetViolationAnalytics returns the violation trend, breakdown and leaders.
+// leaders.Limit defaults to the leader list size like the top param elsewhere.
+func (s *AnalyticsService) GetViolationAnalytics(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter, leaders model.LeaderOptions) (*model.ViolationAnalytics, error) {
+	scope, err := s.resolveSectionScope(ctx, principal, model.SectionViolations)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized, err := s.normalizeFilter(ctx, "GetViolationAnalytics", scope, filter)
+	if err != nil {
+		return nil, err
+	}
+	if scope, err = s.orgFilterScope(ctx, scope, normalized); err != nil {
+		return nil, err
+	}
+	// Violation series only come from the daily view, or from the trips
+	// table for a camera.
+	if normalized.GroupBy == model.GroupByHour {
+		return nil, fmt.Errorf("%w: group_by=hour is not supported for violations", ErrInvalidInterval)
+	}
+	if normalized.CameraID != nil {
+		if normalized, err = limitTripSeries(normalized); err != nil {
+			return nil, err
+		}
+	}
+
+	leaders.Limit = clampTop(leaders.Limit, s.leaderTop)
+	if leaders.OrderBy == "" {
+		leaders.OrderBy = model.LeaderOrderCount
+	}
+	if leaders.By == model.LeaderByRate {
+		leaders.MinTrips = s.rateMinTrips
+	} else {
+		leaders.By = model.LeaderByCount
+	}
+	cacheKey := s.filterCacheKey("violations", scope, filter, leaders)
+	var cached model.ViolationAnalytics
+	if s.cache.Get(ctx, cacheKey, &cached) {
+		return &cached, nil
+	}
+
+	value, err := s.shared(ctx, cacheKey, func(ctx context.Context) (interface{}, error) {
+		return s.loadViolationAnalytics(ctx, scope, normalized, leaders)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*model.ViolationAnalytics), nil
+}
+
+// loadViolationAnalytics runs the violation queries for a cache miss.
+func (s *AnalyticsService) loadViolationAnalytics(ctx context.Context, scope model.Scope, normalized model.AnalyticsFilter, leaders model.LeaderOptions) (*model.ViolationAnalytics, error) {
+	// Severity is not a column: it narrows the status filter instead. No
+	// status left means nothing can match.
+	if len(normalized.Severities) > 0 {
+		normalized.Statuses = s.statusesForSeverities(normalized.Severities, normalized.Statuses)
+		if len(normalized.Statuses) == 0 {
+			return &model.ViolationAnalytics{
+				Series:         []model.SeriesPoint{},
+				Breakdown:      []model.ViolationBreakdown{},
+				Severities:     []model.SeverityBreakdown{},
+				TopContractors: []model.EntityMetric{},
+				TopDrivers:     []model.EntityMetric{},
+				TopCameras:     []model.CameraLoadMetric{},
+			}, nil
+		}
+	}
+
+	series, err := s.analytics.ViolationSeries(ctx, scope, normalized)
+	if err != nil {
+		return nil, err
+	}
+	breakdown, err := s.analytics.ViolationBreakdown(ctx, scope, normalized)
+	if err != nil {
+		return nil, err
+	}
+	topContractors, err := s.analytics.ViolationLeaders(ctx, scope, normalized, "t.contractor_id", leaders)
+	if err != nil {
+		return nil, err
+	}
+	topDrivers, err := s.analytics.ViolationLeaders(ctx, scope, normalized, "tr.driver_id", leaders)
+	if err != nil {
+		return nil, err
+	}
+	// Camera leaders are reported as error events, so they always rank by
+	// count.
+	cameraLeaders := leaders
+	cameraLeaders.By = model.LeaderByCount
+	topCameras, err := s.analytics.ViolationLeaders(ctx, scope, normalized, "tr.camera_id", cameraLeaders)
+	if err != nil {
+		return nil, err
+	}
+	s.shortenEntityNames(topContractors)
+	s.shortenEntityNames(topDrivers)
+
+	result := &model.ViolationAnalytics{
+		Series:         series,
+		Breakdown:      breakdown,
+		Severities:     s.applySeverities(breakdown),
+		TopContractors: topContractors,
+		TopDrivers:     topDrivers,
+		TopCameras:     convertCameraLeaders(topCameras),
+	}
+	return result, nil
+}
+
+// GetPerformanceAnalytics ranks contractors, drivers and vehicles. sort
+// applies to every list that supports its key; the others keep trip count
+// descending.
+func (s *AnalyticsService) GetPerformanceAnalytics(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter, sort model.SortOrder, top int) (*model.PerformanceAnalytics, error) {
+	scope, err := s.resolveSectionScope(ctx, principal, model.SectionPerformance)
+	if err != nil {
+		return nil, err
+	}
+	if sort.Key != "" && !repository.PerformanceSortSupported(sort.Key) {
+		return nil, fmt.Errorf("%w: unknown sort key %q", ErrInvalidSort, sort.Key)
+	}
+
+	normalized, err := s.normalizeFilter(ctx, "GetPerformanceAnalytics", scope, filter)
+	if err != nil {
+		return nil, err
+	}
+	if scope, err = s.orgFilterScope(ctx, scope, normalized); err != nil {
+		return nil, err
+	}
+
+	top = clampTop(top, s.performanceTop)
+	cacheKey := s.filterCacheKey("performance", scope, filter, sort, top)
+	var cached model.PerformanceAnalytics
+	if s.cache.Get(ctx, cacheKey, &cached) {
+		return s.performanceIn(&cached, filter.Unit), nil
+	}
+
+	value, err := s.shared(ctx, cacheKey, func(ctx context.Context) (interface{}, error) {
+		return s.loadPerformanceAnalytics(ctx, scope, normalized, sort, top)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.performanceIn(value.(*model.PerformanceAnalytics), filter.Unit), nil
+}
+
+// loadPerformanceAnalytics runs the performance queries for a cache miss.
+func (s *AnalyticsService) loadPerformanceAnalytics(ctx context.Context, scope model.Scope, normalized model.AnalyticsFilter, sort model.SortOrder, top int) (*model.PerformanceAnalytics, error) {
+	contractors, err := s.analytics.ContractorPerformance(ctx, scope, normalized, top, sort)
+	if err != nil {
+		return nil, err
+	}
+	drivers, err := s.analytics.DriverPerformance(ctx, scope, normalized, top, sort)
+	if err != nil {
+		return nil, err
+	}
+	vehicles, err := s.analytics.VehiclePerformance(ctx, scope, normalized, top, sort)
+	if err != nil {
+		return nil, err
+	}
+	s.shortenContractorNames(contractors)
+	s.shortenDriverNames(drivers)
+
+	result := &model.PerformanceAnalytics{
+		Contractors: contractors,
+		Drivers:     drivers,
+		Vehicles:    vehicles,
+	}
+	return result, nil
+}
+
+func (s *AnalyticsService) GetVolumeEfficiency(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter) ([]model.ContractorPerformance, error) {
+	if principal.IsDriver() {
+		return nil, ErrPermissionDenied
+	}
+
+	scope, err := s.scopes.ResolveScope(ctx, principal)
+	if err != nil || scope.Type == model.ScopeTechnical {
+		return nil, ErrPermissionDenied
+	}
+
+	normalized, err := s.normalizeFilter(ctx, "GetVolumeEfficiency", scope, filter)
+	if err != nil {
+		return nil, err
+	}
+	if scope, err = s.orgFilterScope(ctx, scope, normalized); err != nil {
+		return nil, err
+	}
+	contractors, err := s.analytics.ContractorVolumeEfficiency(ctx, scope, normalized, s.performanceTop)
+	if err != nil {
+		return nil, err
+	}
+	s.shortenContractorNames(contractors)
+	s.convertContractorVolumes(contractors, filter.Unit)
+	return contractors, nil
+}
+
+// GetContractAnalytics lists the contracts in scope along with the top
+// budget, at-risk and over-budget picks. A non-empty filter narrows the
+// contracts before any of these lists are built.
+func (s *AnalyticsService) GetContractAnalytics(ctx context.Context, principal model.Principal, filter model.ContractFilter) (*model.ContractAnalytics, error) {
+	scope, err := s.resolveSectionScope(ctx, principal, model.SectionContracts)
+	if err != nil {
+		return nil, err
+	}
+
+	contracts, err := s.analytics.ContractProgress(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Status != "" || filter.Result != "" {
+		contracts = filterContracts(contracts, func(c model.ContractProgress) bool {
+			return (filter.Status == "" || c.UIStatus == filter.Status) && (filter.Result == "" || c.Result == filter.Result)
+		})
+	}
+
+	summary := make([]model.ContractProgress, len(contracts))
+	copy(summary, contracts)
+
+	sort.Slice(summary, func(i, j int) bool {
+		return summary[i].BudgetProgress > summary[j].BudgetProgress
+	})
+
+	topBudget := takeContracts(summary, 5)
+	atRisk := filterContracts(contracts, func(c model.ContractProgress) bool {
+		return c.UIStatus == model.ContractStatusExpired && c.Result == model.ContractResultFail
+	})
+	budgetIssues := filterContracts(contracts, func(c model.ContractProgress) bool {
+		return c.BudgetProgress > 1.0
+	})
+
+	sort.Slice(atRisk, func(i, j int) bool {
+		return atRisk[i].VolumeProgress < atRisk[j].VolumeProgress
+	})
+	sort.Slice(budgetIssues, func(i, j int) bool {
+		return budgetIssues[i].BudgetProgress > budgetIssues[j].BudgetProgress
+	})
+
+	return &model.ContractAnalytics{
+		Summary:      contracts,
+		TopBudget:    topBudget,
+		AtRisk:       takeContracts(atRisk, 5),
+		BudgetIssues: takeContracts(budgetIssues, 5),
+	}, nil
+}
+
+// GetContractProgressBars projects ContractProgress onto the fields progress
+// bars need.
+func (s *AnalyticsService) GetContractProgressBars(ctx context.Context, principal model.Principal) ([]model.ContractProgressBar, error) {
+	if principal.IsDriver() {
+		return nil, ErrPermissionDenied
+	}
+
+	scope, err := s.scopes.ResolveScope(ctx, principal)
+	if err != nil || scope.Type == model.ScopeTechnical {
+		return nil, ErrPermissionDenied
+	}
+
+	contracts, err := s.analytics.ContractProgress(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	bars := make([]model.ContractProgressBar, 0, len(contracts))
+	for _, contract := range contracts {
+		bars = append(bars, model.ContractProgressBar{
+			ContractID:     contract.ContractID,
+			Name:           contract.Name,
+			VolumeProgress: contract.VolumeProgress,
+			BudgetProgress: contract.BudgetProgress,
+		})
 	}
+	return bars, nil
 }
 
-func (s *AnalyticsService) GetDashboard(ctx context.Context, principal model.Principal, rng model.DateRange) (*model.DashboardMetrics, error) {
+// GetContractSeries returns the contract's progress per day, week or month.
+// Contracts outside the caller's scope are reported as not found.
+func (s *AnalyticsService) GetContractSeries(ctx context.Context, principal model.Principal, contractID uuid.UUID, filter model.AnalyticsFilter) (*model.ContractSeries, error) {
 	if principal.IsDriver() {
 		return nil, ErrPermissionDenied
 	}
 
 	scope, err := s.scopes.ResolveScope(ctx, principal)
+	if err != nil || scope.Type == model.ScopeTechnical {
+		return nil, ErrPermissionDenied
+	}
+
+	normalized, err := s.normalizeFilter(ctx, "GetContractSeries", scope, filter)
 	if err != nil {
-		if errors.Is(err, repository.ErrScopeUnsupported) {
-			return nil, ErrPermissionDenied
-		}
 		return nil, err
 	}
+	if scope, err = s.orgFilterScope(ctx, scope, normalized); err != nil {
+		return nil, err
+	}
+	if normalized.SubDaily() {
+		return nil, fmt.Errorf("%w: contract series support day, week and month buckets only", ErrInvalidInterval)
+	}
 
-	rangeNormalized := s.normalizeRange(rng)
-
-	metrics := &model.DashboardMetrics{GeneratedFor: rangeNormalized}
-
-	if scope.Type != model.ScopeTechnical {
-		stats, err := s.analytics.DashboardStats(ctx, scope, rangeNormalized)
-		if err != nil {
-			return nil, err
-		}
-		areas, err := s.analytics.CleaningAreaActivity(ctx, scope, rangeNormalized)
-		if err != nil {
-			return nil, err
-		}
-		active, idle, err := s.analytics.ContractorActivitySplit(ctx, scope, rangeNormalized)
-		if err != nil {
-			return nil, err
-		}
-		contracts, err := s.analytics.ContractProgress(ctx, scope)
-		if err != nil {
-			return nil, err
-		}
-		mapAreas, mapPolygons, mapCameras, err := s.analytics.MapStates(ctx, scope, rangeNormalized)
-		if err != nil {
-			return nil, err
+	points, err := s.analytics.ContractSeries(ctx, scope, contractID, normalized)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
 		}
+		return nil, err
+	}
+	s.convertContractSeriesVolumes(points, filter.Unit)
+
+	return &model.ContractSeries{ContractID: contractID, Points: points}, nil
+}
 
-		metrics.Stats = stats
-		metrics.Areas = areas
-		metrics.Contractors = model.DashboardContractors{Active: active, Idle: idle}
-		metrics.Contracts = contracts
-		metrics.Map = model.MapSummary{Areas: mapAreas, Polygons: mapPolygons, Cameras: mapCameras}
+func (s *AnalyticsService) GetAreaAnalytics(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter) ([]model.CleaningAreaAnalytics, error) {
+	scope, err := s.resolveSectionScope(ctx, principal, model.SectionAreas)
+	if err != nil {
+		return nil, err
 	}
 
-	cameraLoad, err := s.analytics.CameraLoad(ctx, scope, rangeNormalized)
+	normalized, err := s.normalizeFilter(ctx, "GetAreaAnalytics", scope, filter)
+	if err != nil {
+		return nil, err
+	}
+	if scope, err = s.orgFilterScope(ctx, scope, normalized); err != nil {
+		return nil, err
+	}
+	data, err := s.analytics.CleaningAreaAnalytics(ctx, scope, normalized)
 	if err != nil {
 		return nil, err
 	}
-	metrics.Cameras = cameraLoad
+	s.markNeglected(data)
+	s.convertAreaVolumes(data, filter.Unit)
 
-	return metrics, nil
+	return data, nil
 }
 
-func (s *AnalyticsService) GetTripAnalytics(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter) (*model.TripAnalytics, error) {
-	if principal.IsDriver() {
-		return nil, ErrPermissionDenied
+// GetIdleAreas ranks cleaning areas by idle hours, most neglected first.
+// Only areas with at least one trip in the range are known to the view.
+func (s *AnalyticsService) GetIdleAreas(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter, limit int) ([]model.CleaningAreaAnalytics, error) {
+	areas, err := s.GetAreaAnalytics(ctx, principal, filter)
+	if err != nil {
+		return nil, err
 	}
 
-	scope, err := s.scopes.ResolveScope(ctx, principal)
-	if err != nil || scope.Type == model.ScopeTechnical {
-		return nil, ErrPermissionDenied
+	sort.SliceStable(areas, func(i, j int) bool {
+		if areas[i].IdleHours != areas[j].IdleHours {
+			return areas[i].IdleHours > areas[j].IdleHours
+		}
+		return areas[i].TripCount < areas[j].TripCount
+	})
+	if len(areas) > limit {
+		areas = areas[:limit]
 	}
 
-	normalized := s.normalizeFilter(filter)
+	return areas, nil
+}
 
-	series, err := s.analytics.TripSeries(ctx, scope, normalized)
-	if err != nil {
-		return nil, err
+func (s *AnalyticsService) markNeglected(areas []model.CleaningAreaAnalytics) {
+	if s.areaNeglectAfter <= 0 {
+		return
 	}
-	volumeSeries, err := s.analytics.TripVolumeSeries(ctx, scope, normalized)
-	if err != nil {
-		return nil, err
+	threshold := s.areaNeglectAfter.Hours()
+	for i := range areas {
+		areas[i].Neglected = areas[i].IdleHours >= threshold
 	}
-	topDrivers, err := s.analytics.TopDrivers(ctx, scope, normalized, 5)
+}
+
+// GetDriverKPIs lists driver KPIs. Drivers get a single row: their own.
+func (s *AnalyticsService) GetDriverKPIs(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter, page model.Pagination) (*model.DriverKPIPage, error) {
+	scope, err := s.resolveSectionScope(ctx, principal, model.SectionDrivers)
 	if err != nil {
 		return nil, err
 	}
-	topContractors, err := s.analytics.TopContractors(ctx, scope, normalized, 5)
+
+	normalized, err := s.normalizeFilter(ctx, "GetDriverKPIs", scope, filter)
 	if err != nil {
 		return nil, err
 	}
-	durationStats, err := s.analytics.TripDurationStats(ctx, scope, normalized)
-	if err != nil {
+	if scope, err = s.orgFilterScope(ctx, scope, normalized); err != nil {
 		return nil, err
 	}
-	volumeStats, err := s.analytics.TripVolumeStats(ctx, scope, normalized)
+	kpis, total, err := s.analytics.DriverKPIs(ctx, scope, normalized, page)
 	if err != nil {
 		return nil, err
 	}
+	if kpis == nil {
+		kpis = []model.DriverKPI{}
+	}
+	s.shortenDriverKPINames(kpis)
+	s.convertDriverKPIVolumes(kpis, filter.Unit)
 
-	return &model.TripAnalytics{
-		Series:         series,
-		VolumeSeries:   volumeSeries,
-		TopDrivers:     topDrivers,
-		TopContractors: topContractors,
-		DurationStats:  durationStats,
-		VolumeStats:    volumeStats,
+	return &model.DriverKPIPage{
+		Items:  kpis,
+		Total:  total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
 	}, nil
 }
 
-func (s *AnalyticsService) GetTripDetails(ctx context.Context, principal model.Principal, tripID uuid.UUID) (*model.TripDetails, error) {
-	if principal.IsDriver() {
-		return nil, ErrPermissionDenied
+func (s *AnalyticsService) GetVehicleKPIs(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter) ([]model.VehicleKPI, error) {
+	scope, err := s.resolveSectionScope(ctx, principal, model.SectionVehicles)
+	if err != nil {
+		return nil, err
 	}
 
-	scope, err := s.scopes.ResolveScope(ctx, principal)
-	if err != nil || scope.Type == model.ScopeTechnical {
-		return nil, ErrPermissionDenied
+	normalized, err := s.normalizeFilter(ctx, "GetVehicleKPIs", scope, filter)
+	if err != nil {
+		return nil, err
 	}
-
-	details, err := s.analytics.TripDetails(ctx, scope, tripID)
+	if scope, err = s.orgFilterScope(ctx, scope, normalized); err != nil {
+		return nil, err
+	}
+	kpis, err := s.analytics.VehicleKPIs(ctx, scope, normalized)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrNotFound
-		}
 		return nil, err
 	}
+	s.shortenVehicleKPINames(kpis)
 
-	return details, nil
+	return kpis, nil
 }
 
-func (s *AnalyticsService) GetViolationAnalytics(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter) (*model.ViolationAnalytics, error) {
+// GetCameraEvents lists raw events of a camera. Only city and technical
+// scopes may use it: cameras are shared infrastructure, and contractor or KGU
+// users must not enumerate events of cameras outside their trips.
+func (s *AnalyticsService) GetCameraEvents(ctx context.Context, principal model.Principal, cameraID uuid.UUID, rng model.DateRange, page model.Pagination) (*model.CameraEventPage, error) {
 	if principal.IsDriver() {
 		return nil, ErrPermissionDenied
 	}
 
 	scope, err := s.scopes.ResolveScope(ctx, principal)
-	if err != nil || scope.Type == model.ScopeTechnical {
+	if err != nil || (scope.Type != model.ScopeCity && scope.Type != model.ScopeTechnical) {
 		return nil, ErrPermissionDenied
 	}
 
-	normalized := s.normalizeFilter(filter)
-
-	series, err := s.analytics.ViolationSeries(ctx, scope, normalized)
+	normalized, err := s.normalizeRange(rng)
 	if err != nil {
 		return nil, err
 	}
-	breakdown, err := s.analytics.ViolationBreakdown(ctx, scope, normalized)
+	events, total, err := s.analytics.CameraEvents(ctx, cameraID, normalized, page)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
 		return nil, err
 	}
-	topContractors, err := s.analytics.ViolationLeaders(ctx, scope, normalized, "t.contractor_id", 5)
+	if events == nil {
+		events = []model.CameraEvent{}
+	}
+
+	return &model.CameraEventPage{
+		Items:  events,
+		Total:  total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}, nil
+}
+
+func (s *AnalyticsService) GetTechnicalAnalytics(ctx context.Context, principal model.Principal, rng model.DateRange) (*model.TechnicalAnalytics, error) {
+	scope, err := s.resolveSectionScope(ctx, principal, model.SectionTechnical)
 	if err != nil {
 		return nil, err
 	}
-	topDrivers, err := s.analytics.ViolationLeaders(ctx, scope, normalized, "tr.driver_id", 5)
+
+	normalized, err := s.normalizeRange(rng)
 	if err != nil {
 		return nil, err
 	}
-	topCameras, err := s.analytics.ViolationLeaders(ctx, scope, normalized, "tr.camera_id", 5)
+	data, err := s.analytics.TechnicalAnalytics(ctx, scope, normalized)
 	if err != nil {
 		return nil, err
 	}
 
-	return &model.ViolationAnalytics{
-		Series:         series,
-		Breakdown:      breakdown,
-		TopContractors: topContractors,
-		TopDrivers:     topDrivers,
-		TopCameras:     convertCameraLeaders(topCameras),
-	}, nil
+	return &data, nil
 }
 
-func (s *AnalyticsService) GetPerformanceAnalytics(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter) (*model.PerformanceAnalytics, error) {
+// GetTripQuality reports likely duplicate trips in rng, which scans the trips
+// table and is therefore capped at rawTripsMaxRangeDays.
+func (s *AnalyticsService) GetTripQuality(ctx context.Context, principal model.Principal, rng model.DateRange) (*model.TripQuality, error) {
 	if principal.IsDriver() {
 		return nil, ErrPermissionDenied
 	}
@@ -209,157 +1331,242 @@ func (s *AnalyticsService) GetPerformanceAnalytics(ctx context.Context, principa
 		return nil, ErrPermissionDenied
 	}
 
-	normalized := s.normalizeFilter(filter)
-
-	contractors, err := s.analytics.ContractorPerformance(ctx, scope, normalized, 10)
+	normalized, err := s.normalizeRange(rng)
 	if err != nil {
 		return nil, err
 	}
-	drivers, err := s.analytics.DriverPerformance(ctx, scope, normalized, 10)
+	normalized = capRange(normalized, rawTripsMaxRangeDays)
+
+	quality, err := s.analytics.DuplicateTrips(ctx, scope, normalized, s.duplicateWindow, maxDuplicateSamples)
 	if err != nil {
 		return nil, err
 	}
-	vehicles, err := s.analytics.VehiclePerformance(ctx, scope, normalized, 10)
-	if err != nil {
-		return nil, err
+
+	quality.Range = normalized
+	quality.WindowSeconds = int64(s.duplicateWindow / time.Second)
+	quality.DedupApplied = s.dedupTrips
+	if quality.TotalTrips > 0 {
+		quality.DuplicateRate = float64(quality.DuplicateTrips) / float64(quality.TotalTrips)
 	}
+	return &quality, nil
+}
 
-	return &model.PerformanceAnalytics{
-		Contractors: contractors,
-		Drivers:     drivers,
-		Vehicles:    vehicles,
-	}, nil
+// dedupWindow is the window DashboardStats uses to drop duplicate trips, or
+// zero when deduplication is off.
+func (s *AnalyticsService) dedupWindow() time.Duration {
+	if !s.dedupTrips {
+		return 0
+	}
+	return s.duplicateWindow
 }
 
-func (s *AnalyticsService) GetContractAnalytics(ctx context.Context, principal model.Principal) (*model.ContractAnalytics, error) {
+// GetCameraCoverage reports cameras per polygon. It describes infrastructure
+// rather than trips, so it is limited to technical and city scope.
+func (s *AnalyticsService) GetCameraCoverage(ctx context.Context, principal model.Principal) ([]model.PolygonCoverage, error) {
 	if principal.IsDriver() {
 		return nil, ErrPermissionDenied
 	}
 
 	scope, err := s.scopes.ResolveScope(ctx, principal)
-	if err != nil || scope.Type == model.ScopeTechnical {
+	if err != nil || !(scope.Type == model.ScopeTechnical || scope.AllowsCity()) {
 		return nil, ErrPermissionDenied
 	}
 
-	contracts, err := s.analytics.ContractProgress(ctx, scope)
-	if err != nil {
-		return nil, err
-	}
-
-	summary := make([]model.ContractProgress, len(contracts))
-	copy(summary, contracts)
-
-	sort.Slice(summary, func(i, j int) bool {
-		return summary[i].BudgetProgress > summary[j].BudgetProgress
-	})
-
-	topBudget := takeContracts(summary, 5)
-	atRisk := filterContracts(contracts, func(c model.ContractProgress) bool {
-		return c.UIStatus == "EXPIRED" && c.Result == "FAIL"
-	})
-	budgetIssues := filterContracts(contracts, func(c model.ContractProgress) bool {
-		return c.BudgetProgress > 1.0
-	})
-
-	sort.Slice(atRisk, func(i, j int) bool {
-		return atRisk[i].VolumeProgress < atRisk[j].VolumeProgress
-	})
-	sort.Slice(budgetIssues, func(i, j int) bool {
-		return budgetIssues[i].BudgetProgress > budgetIssues[j].BudgetProgress
-	})
-
-	return &model.ContractAnalytics{
-		Summary:      contracts,
-		TopBudget:    topBudget,
-		AtRisk:       takeContracts(atRisk, 5),
-		BudgetIssues: takeContracts(budgetIssues, 5),
-	}, nil
+	return s.analytics.CameraCoverage(ctx)
 }
 
-func (s *AnalyticsService) GetAreaAnalytics(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter) ([]model.CleaningAreaAnalytics, error) {
+// GetOfflineCameras lists cameras without events in rng. Like the coverage
+// report it is limited to technical and city scope.
+func (s *AnalyticsService) GetOfflineCameras(ctx context.Context, principal model.Principal, rng model.DateRange) ([]model.OfflineCamera, error) {
 	if principal.IsDriver() {
 		return nil, ErrPermissionDenied
 	}
 
 	scope, err := s.scopes.ResolveScope(ctx, principal)
-	if err != nil || scope.Type == model.ScopeTechnical {
+	if err != nil || !(scope.Type == model.ScopeTechnical || scope.AllowsCity()) {
 		return nil, ErrPermissionDenied
 	}
 
-	normalized := s.normalizeFilter(filter)
-	data, err := s.analytics.CleaningAreaAnalytics(ctx, scope, normalized)
+	normalized, err := s.normalizeRange(rng)
 	if err != nil {
 		return nil, err
 	}
 
-	return data, nil
+	return s.analytics.OfflineCameras(ctx, normalized)
 }
 
-func (s *AnalyticsService) GetDriverKPIs(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter) ([]model.DriverKPI, error) {
-	if principal.IsDriver() {
+func (s *AnalyticsService) RefreshMaterializedViews(ctx context.Context, principal model.Principal) ([]model.ViewRefreshResult, error) {
+	if principal.Role != model.UserRoleAkimatAdmin {
 		return nil, ErrPermissionDenied
 	}
 
 	scope, err := s.scopes.ResolveScope(ctx, principal)
-	if err != nil || scope.Type == model.ScopeTechnical {
+	if err != nil || !scope.AllowsCity() {
 		return nil, ErrPermissionDenied
 	}
 
-	normalized := s.normalizeFilter(filter)
-	kpis, err := s.analytics.DriverKPIs(ctx, scope, normalized)
-	if err != nil {
-		return nil, err
-	}
-
-	return kpis, nil
+	return s.refreshViews(ctx), nil
 }
 
-func (s *AnalyticsService) GetVehicleKPIs(ctx context.Context, principal model.Principal, filter model.AnalyticsFilter) ([]model.VehicleKPI, error) {
+// GetConsistency compares each materialized view with live trips over the
+// last days calendar days (today included), so operators can see how far the
+// views lag behind before deciding to refresh.
+func (s *AnalyticsService) GetConsistency(ctx context.Context, principal model.Principal, days int) (*model.ConsistencyReport, error) {
 	if principal.IsDriver() {
 		return nil, ErrPermissionDenied
 	}
 
 	scope, err := s.scopes.ResolveScope(ctx, principal)
-	if err != nil || scope.Type == model.ScopeTechnical {
+	if err != nil || !(scope.AllowsCity() || scope.Type == model.ScopeTechnical) {
 		return nil, ErrPermissionDenied
 	}
 
-	normalized := s.normalizeFilter(filter)
-	kpis, err := s.analytics.VehicleKPIs(ctx, scope, normalized)
-	if err != nil {
-		return nil, err
+	since := time.Now().AddDate(0, 0, -(days - 1))
+	views := s.analytics.MaterializedViews()
+	report := &model.ConsistencyReport{
+		Since: since,
+		Views: make([]model.ViewConsistency, 0, len(views)),
 	}
-
-	return kpis, nil
+	for _, view := range views {
+		result, err := s.analytics.ViewConsistency(ctx, view, since)
+		if err != nil {
+			return nil, err
+		}
+		report.Views = append(report.Views, result)
+	}
+	return report, nil
 }
 
-func (s *AnalyticsService) GetTechnicalAnalytics(ctx context.Context, principal model.Principal, rng model.DateRange) (*model.TechnicalAnalytics, error) {
-	if !(principal.IsLandfill() || principal.IsAkimat() || principal.IsKgu()) {
-		return nil, ErrPermissionDenied
+func (s *AnalyticsService) refreshViews(ctx context.Context) []model.ViewRefreshResult {
+	views := s.analytics.MaterializedViews()
+	results := make([]model.ViewRefreshResult, 0, len(views))
+	for _, view := range views {
+		started := time.Now()
+		err := s.analytics.RefreshMaterializedView(ctx, view)
+		result := model.ViewRefreshResult{
+			View:       view,
+			Status:     "REFRESHED",
+			DurationMs: time.Since(started).Milliseconds(),
+		}
+		switch {
+		case errors.Is(err, repository.ErrViewMissing):
+			result.Status = "SKIPPED"
+		case err != nil:
+			message := err.Error()
+			result.Status = "FAILED"
+			result.Error = &message
+		}
+		results = append(results, result)
 	}
+	// Cached responses may predate the refreshed views.
+	s.cache.Flush(ctx)
+	return results
+}
 
-	scope, err := s.scopes.ResolveScope(ctx, principal)
+func (s *AnalyticsService) normalizeFilter(ctx context.Context, operation string, scope model.Scope, filter model.AnalyticsFilter) (model.AnalyticsFilter, error) {
+	rng, err := s.normalizeRange(filter.Range)
 	if err != nil {
-		return nil, err
+		return filter, err
 	}
-
-	normalized := s.normalizeRange(rng)
-	data, err := s.analytics.TechnicalAnalytics(ctx, scope, normalized)
-	if err != nil {
-		return nil, err
+	filter.Range = rng
+	if err := checkFilterCombination(scope, filter); err != nil {
+		return filter, err
 	}
+	filter.GroupBy = filter.Bucket()
+	s.logFilter(ctx, operation, scope, filter)
+	return filter, nil
+}
 
-	return &data, nil
+// spansFullDay reports whether rng covers at least one day. To is inclusive,
+// so a date-only from=to (midnight to 23:59:59.999999999) counts as a day.
+func spansFullDay(rng model.DateRange) bool {
+	return rng.To.Add(time.Nanosecond).Sub(rng.From) >= 24*time.Hour
 }
 
-func (s *AnalyticsService) normalizeFilter(filter model.AnalyticsFilter) model.AnalyticsFilter {
-	filter.Range = s.normalizeRange(filter.Range)
-	filter.GroupBy = filter.Bucket()
-	return filter
+// checkFilterCombination rejects filters whose parts cannot all hold at
+// once. Merely redundant combinations, such as a driver filtering by their
+// own driver_id, are accepted.
+func checkFilterCombination(scope model.Scope, filter model.AnalyticsFilter) error {
+	switch {
+	case filter.DriverID != nil && scope.Type == model.ScopeTechnical:
+		return fmt.Errorf("%w: driver_id cannot be used with a technical scope, which has no trip data", ErrInvalidFilter)
+	case filter.DriverID != nil && scope.Type == model.ScopeDriver && scope.DriverID != nil && *filter.DriverID != *scope.DriverID:
+		return fmt.Errorf("%w: driver_id must be the caller's own driver", ErrInvalidFilter)
+	case filter.CameraID != nil && filter.GroupByEntity != "":
+		return fmt.Errorf("%w: camera_id cannot be combined with group_by_entity, the per-entity series keep no camera", ErrInvalidFilter)
+	case (filter.GroupBy == model.GroupByWeek || filter.GroupBy == model.GroupByMonth) && filter.Interval == 0 && !spansFullDay(filter.Range):
+		return fmt.Errorf("%w: group_by=%s needs a range of at least one day, use group_by=hour or interval for shorter ranges", ErrInvalidFilter, filter.GroupBy)
+	}
+	return nil
+}
+
+// logFilter logs at debug level the filters a request runs with, so reports
+// of unexpected numbers can be reproduced. It logs ids only, never the
+// principal's token.
+func (s *AnalyticsService) logFilter(ctx context.Context, operation string, scope model.Scope, filter model.AnalyticsFilter) {
+	log := zerolog.Ctx(ctx)
+	if log.GetLevel() == zerolog.Disabled {
+		log = &s.log
+	}
+	event := log.Debug()
+	if event == nil {
+		return
+	}
+	event = event.
+		Str("operation", operation).
+		Str("scope", string(scope.Type)).
+		Time("from", filter.Range.From).
+		Time("to", filter.Range.To).
+		Str("group_by", string(filter.GroupBy)).
+		Str("tz", filter.Location().String())
+	if filter.Interval > 0 {
+		event = event.Str("interval", filter.Interval.String())
+	}
+	if filter.GroupByEntity != "" {
+		event = event.Str("group_by_entity", string(filter.GroupByEntity))
+	}
+	ids := []struct {
+		key string
+		id  *uuid.UUID
+	}{
+		{"contractor_id", filter.ContractorID},
+		{"driver_id", filter.DriverID},
+		{"vehicle_id", filter.VehicleID},
+		{"polygon_id", filter.PolygonID},
+		{"camera_id", filter.CameraID},
+		{"created_by_org_id", filter.CreatedByOrgID},
+	}
+	for _, item := range ids {
+		if item.id != nil {
+			event = event.Str(item.key, item.id.String())
+		}
+	}
+	if filter.ContractorIDs != nil {
+		ids := make([]string, len(filter.ContractorIDs))
+		for i, id := range filter.ContractorIDs {
+			ids[i] = id.String()
+		}
+		event = event.Strs("contractor_ids", ids)
+	}
+	if filter.ContractorName != "" {
+		event = event.Str("contractor_name", filter.ContractorName)
+	}
+	if len(filter.Statuses) > 0 {
+		event = event.Strs("statuses", filter.Statuses)
+	}
+	if len(filter.Severities) > 0 {
+		event = event.Strs("severities", filter.Severities)
+	}
+	event.Msg("analytics filter")
 }
 
-func (s *AnalyticsService) normalizeRange(rng model.DateRange) model.DateRange {
-	if rng.To.IsZero() {
+// normalizeRange fills in a missing bound and caps the range at maxRange
+// days. An explicit From wins over a defaulted To: from=<long ago> alone
+// yields the maxRange days starting at From rather than the days up to now.
+// With rng.Strict a range over maxRange fails with ErrRangeTooLarge instead.
+func (s *AnalyticsService) normalizeRange(rng model.DateRange) (model.DateRange, error) {
+	toDefaulted := rng.To.IsZero()
+	if toDefaulted {
 		rng.To = time.Now()
 	}
 	if rng.From.IsZero() {
@@ -369,6 +1576,120 @@ func (s *AnalyticsService) normalizeRange(rng model.DateRange) model.DateRange {
 		rng.From = rng.To.Add(-24 * time.Hour)
 	}
 	maxDuration := time.Duration(s.maxRange) * 24 * time.Hour
+	if rng.To.Sub(rng.From) > maxDuration {
+		if rng.Strict {
+			return rng, fmt.Errorf("%w: at most %d days are allowed", ErrRangeTooLarge, s.maxRange)
+		}
+		if toDefaulted {
+			rng.To = rng.From.Add(maxDuration)
+		} else {
+			rng.From = rng.To.Add(-maxDuration)
+		}
+	}
+	return rng, nil
+}
+
+// filterCacheKey keys a filtered response by scope, the filter as requested
+// and any extra parameters. The location is added by name since
+// *time.Location does not survive JSON encoding. The unit is left out:
+// responses are cached in m³ and converted afterwards.
+func (s *AnalyticsService) filterCacheKey(endpoint string, scope model.Scope, filter model.AnalyticsFilter, extra ...interface{}) string {
+	filter.Unit = ""
+	parts := append([]interface{}{scope, filter, filter.Location().String()}, extra...)
+	return s.cache.Key(endpoint, parts...)
+}
+
+// orgFilterScope narrows a city scope to the tickets created by
+// filter.CreatedByOrgID by handing the queries a KGU scope of that
+// organization without its contractors. The caller's own scope is unchanged.
+func (s *AnalyticsService) orgFilterScope(ctx context.Context, scope model.Scope, filter model.AnalyticsFilter) (model.Scope, error) {
+	if filter.CreatedByOrgID == nil {
+		return scope, nil
+	}
+	if scope.Type != model.ScopeCity {
+		return scope, fmt.Errorf("%w: only city users may filter by the creating organization", ErrInvalidOrgFilter)
+	}
+	exists, err := s.scopes.OrganizationExists(ctx, *filter.CreatedByOrgID)
+	if err != nil {
+		return scope, err
+	}
+	if !exists {
+		return scope, fmt.Errorf("%w: unknown organization", ErrInvalidOrgFilter)
+	}
+	orgID := *filter.CreatedByOrgID
+	return model.Scope{Type: model.ScopeKgu, OrgID: &orgID, OrganizationIDs: []uuid.UUID{orgID}}, nil
+}
+
+// activeTripCutoff returns the earliest entry time an open trip may have to
+// still be reported as active, or the zero time when no cutoff is configured.
+func (s *AnalyticsService) activeTripCutoff() time.Time {
+	if s.activeTripMaxAge <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(-s.activeTripMaxAge)
+}
+
+// previousPeriod returns the window of equal length ending right before rng.
+// DashboardStats filters with BETWEEN, so the end is pulled back by the
+// smallest Postgres timestamp step to keep the boundary instant out of it.
+func previousPeriod(rng model.DateRange) model.DateRange {
+	length := rng.To.Sub(rng.From)
+	to := rng.From.Add(-time.Microsecond)
+	return model.DateRange{From: to.Add(-length), To: to}
+}
+
+func percentChange(current, previous int64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	change := math.Round(float64(current-previous)/float64(previous)*10000) / 100
+	return &change
+}
+
+// summarizeSeries computes the headline numbers of a series so clients do
+// not have to re-add the points themselves.
+func summarizeSeries(points []model.SeriesPoint) model.SeriesSummary {
+	var summary model.SeriesSummary
+	for i, point := range points {
+		summary.Total += point.Count
+		summary.TotalValue += point.Value
+		if summary.PeakBucket == nil || point.Count > summary.PeakValue {
+			summary.PeakBucket = &points[i].Bucket
+			summary.PeakValue = point.Count
+		}
+	}
+	if len(points) > 0 {
+		summary.AvgPerBucket = float64(summary.Total) / float64(len(points))
+	}
+	return summary
+}
+
+// limitTripSeries caps the range of series read from the trips table:
+// rawTripsMaxRangeDays for interval buckets and day/week/month buckets of a
+// camera, hourlyMaxRangeDays for group_by=hour (interval takes precedence
+// when both are set).
+func limitTripSeries(filter model.AnalyticsFilter) (model.AnalyticsFilter, error) {
+	days := rawTripsMaxRangeDays
+	if filter.Interval <= 0 && filter.GroupBy == model.GroupByHour {
+		days = hourlyMaxRangeDays
+	}
+	filter.Range = capRange(filter.Range, days)
+	return filter, checkIntervalPoints(filter)
+}
+
+func checkIntervalPoints(filter model.AnalyticsFilter) error {
+	if filter.Interval <= 0 {
+		return nil
+	}
+	points := int64(filter.Range.To.Sub(filter.Range.From)/filter.Interval) + 1
+	if points > maxIntervalPoints {
+		return fmt.Errorf("%w: %s over the requested range yields %d buckets, at most %d allowed", ErrInvalidInterval, filter.Interval, points, maxIntervalPoints)
+	}
+	return nil
+}
+
+func capRange(rng model.DateRange, days int) model.DateRange {
+	maxDuration := time.Duration(days) * 24 * time.Hour
 	if rng.To.Sub(rng.From) > maxDuration {
 		rng.From = rng.To.Add(-maxDuration)
 	}
@@ -387,6 +1708,18 @@ func convertCameraLeaders(metrics []model.EntityMetric) []model.CameraLoadMetric
 	return result
 }
 
+// clampTop applies the default leader list size when top is unset and caps
+// it at maxTop.
+func clampTop(top, defaultTop int) int {
+	if top <= 0 {
+		return defaultTop
+	}
+	if top > maxTop {
+		return maxTop
+	}
+	return top
+}
+
 func takeContracts(items []model.ContractProgress, limit int) []model.ContractProgress {
 	if len(items) <= limit {
 		return items
@@ -403,3 +1736,30 @@ func filterContracts(items []model.ContractProgress, predicate func(model.Contra
 	}
 	return result
 }
+
+// Readiness reports whether the service can serve traffic. The database is
+// required; missing materialized views only degrade the result because the
+// affected endpoints answer with empty data until the next migration.
+func (s *AnalyticsService) Readiness(ctx context.Context) model.Readiness {
+	result := model.Readiness{Status: "READY", Checks: map[string]string{}}
+
+	if err := s.analytics.Ping(ctx); err != nil {
+		result.Status = "UNAVAILABLE"
+		result.Failed = "database"
+		result.Checks["database"] = err.Error()
+		return result
+	}
+	result.Checks["database"] = "ok"
+
+	if missing := s.analytics.MissingViews(ctx); len(missing) > 0 {
+		result.Status = "DEGRADED"
+		result.Checks["materialized_views"] = "missing"
+		for _, view := range missing {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("materialized view %s is missing", view))
+		}
+	} else {
+		result.Checks["materialized_views"] = "ok"
+	}
+
+	return result
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"analytics-service/internal/model"
+)
+
+func TestCheckFilterCombinationRejectsContradictions(t *testing.T) {
+	driverID, otherDriverID, cameraID := uuid.New(), uuid.New(), uuid.New()
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := model.DateRange{From: from, To: from.Add(24*time.Hour - time.Nanosecond)}
+	halfDay := model.DateRange{From: from, To: from.Add(12 * time.Hour)}
+
+	tests := []struct {
+		name   string
+		scope  model.Scope
+		filter model.AnalyticsFilter
+	}{
+		{"driver_id with technical scope", model.Scope{Type: model.ScopeTechnical},
+			model.AnalyticsFilter{Range: day, DriverID: &driverID}},
+		{"another driver's driver_id", model.Scope{Type: model.ScopeDriver, DriverID: &driverID},
+			model.AnalyticsFilter{Range: day, DriverID: &otherDriverID}},
+		{"camera_id with group_by_entity", model.Scope{Type: model.ScopeCity},
+			model.AnalyticsFilter{Range: day, CameraID: &cameraID, GroupByEntity: model.GroupByEntityContractor}},
+		{"group_by=week under a day", model.Scope{Type: model.ScopeCity},
+			model.AnalyticsFilter{Range: halfDay, GroupBy: model.GroupByWeek}},
+		{"group_by=month under a day", model.Scope{Type: model.ScopeCity},
+			model.AnalyticsFilter{Range: halfDay, GroupBy: model.GroupByMonth}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkFilterCombination(tt.scope, tt.filter); !errors.Is(err, ErrInvalidFilter) {
+				t.Errorf("got %v, want ErrInvalidFilter", err)
+			}
+		})
+	}
+}
+
+func TestCheckFilterCombinationAcceptsRedundantFilters(t *testing.T) {
+	driverID := uuid.New()
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		scope  model.Scope
+		filter model.AnalyticsFilter
+	}{
+		{"own driver_id", model.Scope{Type: model.ScopeDriver, DriverID: &driverID},
+			model.AnalyticsFilter{Range: model.DateRange{From: from, To: from.AddDate(0, 0, 7)}, DriverID: &driverID}},
+		{"group_by=week on a date-only single day", model.Scope{Type: model.ScopeCity},
+			model.AnalyticsFilter{Range: model.DateRange{From: from, To: from.Add(24*time.Hour - time.Nanosecond)}, GroupBy: model.GroupByWeek}},
+		{"group_by=week with interval under a day", model.Scope{Type: model.ScopeCity},
+			model.AnalyticsFilter{Range: model.DateRange{From: from, To: from.Add(6 * time.Hour)}, GroupBy: model.GroupByWeek, Interval: time.Hour}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkFilterCombination(tt.scope, tt.filter); err != nil {
+				t.Errorf("got %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestNormalizeRange(t *testing.T) {
+	s := &AnalyticsService{defaultRange: 7, maxRange: 30}
+	day := 24 * time.Hour
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("both zero", func(t *testing.T) {
+		before := time.Now()
+		got, err := s.normalizeRange(model.DateRange{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.To.Before(before) || time.Since(got.To) > time.Minute {
+			t.Errorf("to %v is not now", got.To)
+		}
+		if got.To.Sub(got.From) != 7*day {
+			t.Errorf("got %v, want the default 7 days", got.To.Sub(got.From))
+		}
+	})
+	t.Run("only to", func(t *testing.T) {
+		got, err := s.normalizeRange(model.DateRange{To: to})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.To.Equal(to) || !got.From.Equal(to.AddDate(0, 0, -7)) {
+			t.Errorf("got %v..%v, want the 7 days before %v", got.From, got.To, to)
+		}
+	})
+	t.Run("only from", func(t *testing.T) {
+		from := time.Now().AddDate(0, 0, -3)
+		got, err := s.normalizeRange(model.DateRange{From: from})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.From.Equal(from) || time.Since(got.To) > time.Minute {
+			t.Errorf("got %v..%v, want %v to now", got.From, got.To, from)
+		}
+	})
+	t.Run("only from over the maximum", func(t *testing.T) {
+		from := time.Now().AddDate(0, 0, -100)
+		got, err := s.normalizeRange(model.DateRange{From: from})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.From.Equal(from) || !got.To.Equal(from.Add(30*day)) {
+			t.Errorf("got %v..%v, want the 30 days from %v", got.From, got.To, from)
+		}
+	})
+	t.Run("reversed", func(t *testing.T) {
+		got, err := s.normalizeRange(model.DateRange{From: to.Add(day), To: to})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.To.Equal(to) || !got.From.Equal(to.Add(-day)) {
+			t.Errorf("got %v..%v, want the day before %v", got.From, got.To, to)
+		}
+	})
+	t.Run("over the maximum", func(t *testing.T) {
+		got, err := s.normalizeRange(model.DateRange{From: to.AddDate(0, 0, -60), To: to})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.To.Equal(to) || !got.From.Equal(to.Add(-30*day)) {
+			t.Errorf("got %v..%v, want the 30 days before %v", got.From, got.To, to)
+		}
+	})
+	t.Run("strict over the maximum", func(t *testing.T) {
+		_, err := s.normalizeRange(model.DateRange{From: to.AddDate(0, 0, -60), To: to, Strict: true})
+		if !errors.Is(err, ErrRangeTooLarge) {
+			t.Errorf("got %v, want ErrRangeTooLarge", err)
+		}
+	})
+	t.Run("strict within the maximum", func(t *testing.T) {
+		rng := model.DateRange{From: to.AddDate(0, 0, -30), To: to, Strict: true}
+		got, err := s.normalizeRange(rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.From.Equal(rng.From) || !got.To.Equal(rng.To) {
+			t.Errorf("got %v..%v, want the range unchanged", got.From, got.To)
+		}
+	})
+}
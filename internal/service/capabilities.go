@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"analytics-service/internal/model"
+	"analytics-service/internal/repository"
+)
+
+// sectionAllowed is the access rule of each analytics section. The section
+// entry points check it before running any query and Capabilities reports
+// it, so client menus and server guards cannot drift apart.
+func sectionAllowed(section model.Section, principal model.Principal, scope model.Scope) bool {
+	switch section {
+	case model.SectionTechnical:
+		// Decided by scope so a role mapped to TECHNICAL gets it without a
+		// code change. Landfills share the contractor scope with contractors,
+		// who have no access, so they are still told apart by role.
+		switch scope.Type {
+		case model.ScopeTechnical, model.ScopeCity, model.ScopeKgu:
+			return true
+		case model.ScopeContractor:
+			return principal.IsLandfill()
+		default:
+			return false
+		}
+	case model.SectionDashboard:
+		return !principal.IsDriver()
+	case model.SectionTripList:
+		// Technical users get an empty list rather than a 403.
+		return true
+	case model.SectionDrivers:
+		return scope.Type != model.ScopeTechnical
+	default:
+		return !principal.IsDriver() && scope.Type != model.ScopeTechnical
+	}
+}
+
+// resolveSectionScope resolves the principal's scope and checks it against
+// section. Roles without analytics access are denied.
+func (s *AnalyticsService) resolveSectionScope(ctx context.Context, principal model.Principal, section model.Section) (model.Scope, error) {
+	scope, err := s.scopes.ResolveScope(ctx, principal)
+	if err != nil {
+		if errors.Is(err, repository.ErrScopeUnsupported) {
+			return model.Scope{}, ErrPermissionDenied
+		}
+		return model.Scope{}, err
+	}
+	if !sectionAllowed(section, principal, scope) {
+		return model.Scope{}, ErrPermissionDenied
+	}
+	return scope, nil
+}
+
+// Capabilities reports the sections the principal may open. A role without
+// analytics access gets every section set to false rather than an error.
+func (s *AnalyticsService) Capabilities(ctx context.Context, principal model.Principal) (*model.Capabilities, error) {
+	capabilities := &model.Capabilities{Sections: make(map[model.Section]bool, len(model.Sections))}
+	for _, section := range model.Sections {
+		capabilities.Sections[section] = false
+	}
+
+	scope, err := s.scopes.ResolveScope(ctx, principal)
+	if err != nil {
+		if errors.Is(err, repository.ErrScopeUnsupported) {
+			return capabilities, nil
+		}
+		return nil, err
+	}
+
+	capabilities.Scope = scope.Type
+	for _, section := range model.Sections {
+		capabilities.Sections[section] = sectionAllowed(section, principal, scope)
+	}
+	return capabilities, nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import "context"
+
+// shared returns the response stored under key, loading it at most once at a
+// time: concurrent callers with the same key (same endpoint, scope and
+// filter) wait for the running load instead of starting their own, which
+// keeps a cache flush after a view refresh from turning into a stampede of
+// identical queries. Successful loads are written to the cache.
+//
+// The result is shared between the callers and must not be modified.
+func (s *AnalyticsService) shared(ctx context.Context, key string, load func(context.Context) (interface{}, error)) (interface{}, error) {
+	if s.inflight == nil || key == "" {
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.cache.Set(ctx, key, value)
+		return value, nil
+	}
+
+	results := s.inflight.DoChan(key, func() (interface{}, error) {
+		// The load outlives a caller that gives up, so the others still get
+		// the result, but it keeps that caller's deadline.
+		loadCtx := context.WithoutCancel(ctx)
+		if deadline, ok := ctx.Deadline(); ok {
+			var cancel context.CancelFunc
+			loadCtx, cancel = context.WithDeadline(loadCtx, deadline)
+			defer cancel()
+		}
+		value, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+		s.cache.Set(loadCtx, key, value)
+		return value, nil
+	})
+
+	select {
+	case result := <-results:
+		return result.Val, result.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
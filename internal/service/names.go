@@ -0,0 +1,51 @@
+package service
+
+import "analytics-service/internal/model"
+
+const nameEllipsis = "…"
+
+// shortenName truncates *name to maxNameLength characters in place and
+// returns the original value, or nil when the name was left untouched.
+func (s *AnalyticsService) shortenName(name *string) *string {
+	if s.maxNameLength <= 0 || name == nil {
+		return nil
+	}
+	runes := []rune(*name)
+	if len(runes) <= s.maxNameLength {
+		return nil
+	}
+	full := *name
+	*name = string(runes[:s.maxNameLength]) + nameEllipsis
+	return &full
+}
+
+func (s *AnalyticsService) shortenEntityNames(items []model.EntityMetric) {
+	for i := range items {
+		items[i].FullName = s.shortenName(&items[i].Name)
+	}
+}
+
+func (s *AnalyticsService) shortenContractorNames(items []model.ContractorPerformance) {
+	for i := range items {
+		items[i].ContractorFullName = s.shortenName(&items[i].ContractorName)
+	}
+}
+
+func (s *AnalyticsService) shortenDriverNames(items []model.DriverPerformance) {
+	for i := range items {
+		items[i].DriverFullName = s.shortenName(&items[i].DriverName)
+	}
+}
+
+func (s *AnalyticsService) shortenDriverKPINames(items []model.DriverKPI) {
+	for i := range items {
+		items[i].DriverFullName = s.shortenName(&items[i].DriverName)
+		items[i].ContractorFullName = s.shortenName(items[i].ContractorName)
+	}
+}
+
+func (s *AnalyticsService) shortenVehicleKPINames(items []model.VehicleKPI) {
+	for i := range items {
+		items[i].ContractorFullName = s.shortenName(items[i].ContractorName)
+	}
+}
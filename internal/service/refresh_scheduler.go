@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type RefreshScheduler struct {
+	analytics *AnalyticsService
+	interval  time.Duration
+	log       zerolog.Logger
+}
+
+func NewRefreshScheduler(analytics *AnalyticsService, interval time.Duration, log zerolog.Logger) *RefreshScheduler {
+	return &RefreshScheduler{analytics: analytics, interval: interval, log: log}
+}
+
+// Run refreshes the materialized views every interval until ctx is cancelled.
+// Refreshes run on the ticker goroutine itself, so a slow cycle delays the next
+// tick instead of overlapping with it.
+func (s *RefreshScheduler) Run(ctx context.Context) {
+	if s.interval <= 0 {
+		s.log.Info().Msg("materialized view refresh scheduler disabled")
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.log.Info().Dur("interval", s.interval).Msg("materialized view refresh scheduler started")
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info().Msg("materialized view refresh scheduler stopped")
+			return
+		case <-ticker.C:
+			s.refresh(ctx)
+		}
+	}
+}
+
+// refresh runs one cycle. Each cycle gets its own request id so its log lines
+// can be told apart from those of manual refreshes.
+func (s *RefreshScheduler) refresh(ctx context.Context) {
+	log := s.log.With().Str("request_id", uuid.NewString()).Logger()
+	ctx = log.WithContext(ctx)
+
+	started := time.Now()
+	results := s.analytics.refreshViews(ctx)
+
+	failed := 0
+	for _, result := range results {
+		event := log.Debug()
+		if result.Error != nil {
+			failed++
+			event = log.Warn().Str("error", *result.Error)
+		}
+		event.Str("view", result.View).
+			Str("status", result.Status).
+			Int64("duration_ms", result.DurationMs).
+			Msg("materialized view refresh")
+	}
+
+	log.Info().
+		Int("views", len(results)).
+		Int("failed", failed).
+		Dur("elapsed", time.Since(started)).
+		Msg("materialized view refresh cycle finished")
+}
@@ -0,0 +1,72 @@
+package service
+
+import "analytics-service/internal/model"
+
+// defaultViolationSeverities is the status → severity mapping used unless
+// overridden by Options.ViolationSeverities.
+var defaultViolationSeverities = map[string]string{
+	model.TripStatusMismatchPlate: model.SeverityHigh,
+	model.TripStatusNoLPREvent:    model.SeverityMedium,
+	model.TripStatusNoVolumeEvent: model.SeverityMedium,
+	model.TripStatusCameraError:   model.SeverityLow,
+}
+
+var severityOrder = []string{model.SeverityHigh, model.SeverityMedium, model.SeverityLow, model.SeverityUnknown}
+
+func (s *AnalyticsService) severityOf(status string) string {
+	if severity, ok := s.severities[status]; ok {
+		return severity
+	}
+	return model.SeverityUnknown
+}
+
+// statusesForSeverities returns the violation statuses mapped to one of
+// severities, narrowed to statuses when that is not empty.
+func (s *AnalyticsService) statusesForSeverities(severities, statuses []string) []string {
+	wanted := make(map[string]bool, len(severities))
+	for _, severity := range severities {
+		wanted[severity] = true
+	}
+	allowed := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		allowed[status] = true
+	}
+
+	result := make([]string, 0)
+	for _, status := range model.ViolationStatuses() {
+		if !wanted[s.severityOf(status)] {
+			continue
+		}
+		if len(statuses) > 0 && !allowed[status] {
+			continue
+		}
+		result = append(result, status)
+	}
+	return result
+}
+
+// applySeverities tags each breakdown entry with its severity and returns
+// the totals per severity, most severe first.
+func (s *AnalyticsService) applySeverities(breakdown []model.ViolationBreakdown) []model.SeverityBreakdown {
+	counts := make(map[string]int64, len(severityOrder))
+	total := int64(0)
+	for i := range breakdown {
+		breakdown[i].Severity = s.severityOf(breakdown[i].Type)
+		counts[breakdown[i].Severity] += breakdown[i].Count
+		total += breakdown[i].Count
+	}
+
+	result := make([]model.SeverityBreakdown, 0, len(counts))
+	for _, severity := range severityOrder {
+		count, ok := counts[severity]
+		if !ok {
+			continue
+		}
+		share := 0.0
+		if total > 0 {
+			share = float64(count) / float64(total)
+		}
+		result = append(result, model.SeverityBreakdown{Severity: severity, Count: count, Share: share})
+	}
+	return result
+}
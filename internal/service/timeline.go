@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"analytics-service/internal/model"
+)
+
+// GetTripTimeline returns the events of a trip in chronological order. It is
+// scoped exactly like GetTripDetails, which it is built from.
+func (s *AnalyticsService) GetTripTimeline(ctx context.Context, principal model.Principal, tripID uuid.UUID) (*model.TripTimeline, error) {
+	details, err := s.GetTripDetails(ctx, principal, tripID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.TripTimeline{
+		TripID:  details.TripID,
+		Status:  details.Status,
+		Entries: tripTimeline(details),
+	}, nil
+}
+
+// tripTimeline flattens the trip boundaries, its camera events and its
+// violations into one list sorted by time. Entries at the same instant keep
+// the order they are added in, so the entry comes before its events.
+func tripTimeline(details *model.TripDetails) []model.TripTimelineEntry {
+	entries := []model.TripTimelineEntry{{Type: model.TimelineTripEntry, At: details.EntryAt}}
+
+	events := []struct {
+		kind  string
+		event *model.TripEvent
+	}{
+		{model.TimelineEntryLPR, details.Events.EntryLPR},
+		{model.TimelineEntryVolume, details.Events.EntryVolume},
+		{model.TimelineExitLPR, details.Events.ExitLPR},
+		{model.TimelineExitVolume, details.Events.ExitVolume},
+	}
+	for _, item := range events {
+		if item.event == nil {
+			continue
+		}
+		eventID, cameraID := item.event.EventID, item.event.CameraID
+		entries = append(entries, model.TripTimelineEntry{
+			Type:     item.kind,
+			At:       item.event.Captured,
+			EventID:  &eventID,
+			CameraID: &cameraID,
+			PhotoURL: item.event.PhotoURL,
+		})
+	}
+
+	if details.ExitAt != nil {
+		entries = append(entries, model.TripTimelineEntry{Type: model.TimelineTripExit, At: *details.ExitAt})
+	}
+	for i := range details.Violations {
+		entries = append(entries, model.TripTimelineEntry{
+			Type:      model.TimelineViolation,
+			At:        details.Violations[i].At,
+			Violation: &details.Violations[i],
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].At.Before(entries[j].At)
+	})
+	return entries
+}
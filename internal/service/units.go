@@ -0,0 +1,157 @@
+package service
+
+import "analytics-service/internal/model"
+
+// Volumes are queried and cached in m³; the converters below return copies
+// in the requested unit so cached and shared results stay untouched.
+
+// volumeFactor is the multiplier from m³ to unit.
+func (s *AnalyticsService) volumeFactor(unit model.VolumeUnit) float64 {
+	if unit == model.VolumeUnitTonnes {
+		return s.tonnesPerM3
+	}
+	return 1
+}
+
+func convertSeries(points []model.SeriesPoint, factor float64) []model.SeriesPoint {
+	if points == nil {
+		return nil
+	}
+	out := make([]model.SeriesPoint, len(points))
+	for i, point := range points {
+		point.Value *= factor
+		out[i] = point
+	}
+	return out
+}
+
+func convertEntityMetrics(items []model.EntityMetric, factor float64) []model.EntityMetric {
+	if items == nil {
+		return nil
+	}
+	out := make([]model.EntityMetric, len(items))
+	for i, item := range items {
+		item.Volume *= factor
+		out[i] = item
+	}
+	return out
+}
+
+func (s *AnalyticsService) tripAnalyticsIn(a *model.TripAnalytics, unit model.VolumeUnit) *model.TripAnalytics {
+	factor := s.volumeFactor(unit)
+	if factor == 1 {
+		return a
+	}
+	out := *a
+	out.Series = convertSeries(a.Series, factor)
+	out.SeriesSummary.TotalValue *= factor
+	out.VolumeSeries = convertSeries(a.VolumeSeries, factor)
+	out.VolumeSummary.TotalValue *= factor
+	if a.EntitySeries != nil {
+		out.EntitySeries = make(map[string][]model.SeriesPoint, len(a.EntitySeries))
+		for id, points := range a.EntitySeries {
+			out.EntitySeries[id] = convertSeries(points, factor)
+		}
+	}
+	out.TopDrivers = convertEntityMetrics(a.TopDrivers, factor)
+	out.TopContractors = convertEntityMetrics(a.TopContractors, factor)
+
+	stats := &out.VolumeStats
+	for _, value := range []*float64{
+		&stats.AvgVolume, &stats.MaxVolume, &stats.MinVolume,
+		&stats.AvgExitVolume, &stats.MaxExitVolume, &stats.MinExitVolume,
+		&stats.TotalVolume, &stats.TotalExitVolume,
+		&stats.AvgNetVolume, &stats.TotalNetVolume,
+	} {
+		*value *= factor
+	}
+	return &out
+}
+
+func (s *AnalyticsService) performanceIn(a *model.PerformanceAnalytics, unit model.VolumeUnit) *model.PerformanceAnalytics {
+	factor := s.volumeFactor(unit)
+	if factor == 1 {
+		return a
+	}
+	out := *a
+	out.Contractors = make([]model.ContractorPerformance, len(a.Contractors))
+	for i, item := range a.Contractors {
+		item.AvgVolume *= factor
+		item.TotalVolume *= factor
+		item.VolumePerTrip *= factor
+		out.Contractors[i] = item
+	}
+	out.Drivers = make([]model.DriverPerformance, len(a.Drivers))
+	for i, item := range a.Drivers {
+		item.AvgVolume *= factor
+		out.Drivers[i] = item
+	}
+	return &out
+}
+
+// The area and driver KPI lists are built per request, so they are
+// converted in place.
+
+func (s *AnalyticsService) convertAreaVolumes(areas []model.CleaningAreaAnalytics, unit model.VolumeUnit) {
+	factor := s.volumeFactor(unit)
+	for i := range areas {
+		areas[i].VolumeM3 *= factor
+	}
+}
+
+func (s *AnalyticsService) convertDriverKPIVolumes(items []model.DriverKPI, unit model.VolumeUnit) {
+	factor := s.volumeFactor(unit)
+	for i := range items {
+		items[i].AvgVolume *= factor
+	}
+}
+
+func (s *AnalyticsService) convertContractorVolumes(items []model.ContractorPerformance, unit model.VolumeUnit) {
+	factor := s.volumeFactor(unit)
+	for i := range items {
+		items[i].AvgVolume *= factor
+		items[i].TotalVolume *= factor
+		items[i].VolumePerTrip *= factor
+	}
+}
+
+func (s *AnalyticsService) convertKguVolumes(items []model.KguComparison, unit model.VolumeUnit) {
+	factor := s.volumeFactor(unit)
+	for i := range items {
+		items[i].VolumeM3 *= factor
+	}
+}
+
+func (s *AnalyticsService) convertTripListVolumes(items []model.TripListItem, unit model.VolumeUnit) {
+	factor := s.volumeFactor(unit)
+	for i := range items {
+		for _, value := range []*float64{items[i].VolumeEntry, items[i].VolumeExit} {
+			if value != nil {
+				*value *= factor
+			}
+		}
+	}
+}
+
+func (s *AnalyticsService) convertContractVolumes(items []model.ContractProgress, unit model.VolumeUnit) {
+	factor := s.volumeFactor(unit)
+	for i := range items {
+		items[i].MinimalVolume *= factor
+		items[i].TotalVolume *= factor
+		items[i].AvgVolumePerDay *= factor
+	}
+}
+
+func (s *AnalyticsService) convertContractSeriesVolumes(points []model.ContractSeriesPoint, unit model.VolumeUnit) {
+	factor := s.volumeFactor(unit)
+	for i := range points {
+		points[i].TotalVolume *= factor
+	}
+}
+
+func (s *AnalyticsService) convertRankValues(points []model.ContractorRankPoint, unit model.VolumeUnit) {
+	factor := s.volumeFactor(unit)
+	for i := range points {
+		points[i].Value *= factor
+	}
+}